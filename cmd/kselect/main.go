@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/bangmodtechnology/kselect/pkg/completion"
@@ -14,6 +18,7 @@ import (
 	"github.com/bangmodtechnology/kselect/pkg/parser"
 	"github.com/bangmodtechnology/kselect/pkg/registry"
 	"github.com/bangmodtechnology/kselect/pkg/repl"
+	"github.com/bangmodtechnology/kselect/pkg/source"
 	"github.com/bangmodtechnology/kselect/pkg/tui"
 	"github.com/bangmodtechnology/kselect/pkg/validator"
 
@@ -26,6 +31,9 @@ var (
 	Codename = ""
 )
 
+// sessionCacheTTL is the default --cache-ttl of the REPL and the TUI.
+const sessionCacheTTL = 30 * time.Second
+
 func main() {
 	// Extract flags from anywhere in args (Go flag stops at first non-flag arg)
 	rawArgs := os.Args[1:]
@@ -50,6 +58,21 @@ func main() {
 
 	interval := flag.Duration("interval", 2*time.Second, "Watch refresh interval")
 
+	crdFile := flag.String("file", "", "CRD manifest for plugin generate")
+	flag.StringVar(crdFile, "f", "", "CRD manifest for plugin generate (shorthand)")
+
+	crdSchemas := flag.Bool("crd-schema", false, "Derive fields of discovered CRDs from their schema")
+
+	var from stringList
+	flag.Var(&from, "from", "Query manifests (file, directory or - for stdin) instead of the cluster; repeatable")
+	params := paramList{}
+	flag.Var(params, "param", "Value of a query placeholder as name=value (:name, or $n for a number n); repeatable")
+	before := flag.String("before", "", "Snapshot before the change, for diff")
+	after := flag.String("after", "", "Snapshot after the change, for diff")
+	timeout := flag.Duration("timeout", 0, "Give up on a query after this long (0 waits indefinitely)")
+	cacheTTL := flag.Duration("cache-ttl", 0, "How long listed objects are reused by later lists in a session (default 30s with -i or --tui, otherwise 0; 0 disables)")
+	snapshot := flag.String("snapshot", "", "Query a cluster-info dump or must-gather (directory or tar.gz) instead of the cluster")
+
 	interactive := flag.Bool("interactive", false, "Interactive REPL mode")
 	flag.BoolVar(interactive, "i", false, "Interactive REPL mode (shorthand)")
 
@@ -79,6 +102,12 @@ func main() {
 	// Include any remaining non-flag args from flag.Parse
 	queryArgs = append(queryArgs, flag.Args()...)
 
+	// Only the REPL and the TUI rerun queries, so only they cache unless
+	// --cache-ttl says otherwise; one-shot runs and --watch read fresh objects
+	if (*interactive || *tuiMode) && !flagGiven("cache-ttl") {
+		*cacheTTL = sessionCacheTTL
+	}
+
 	// Color: auto-detect TTY, respect --no-color flag
 	format := output.Format(*outputFormat)
 	useColor := !*noColor && output.DetectColor() && format != output.FormatCSV
@@ -98,11 +127,27 @@ func main() {
 		return
 	}
 
-	// Load plugins
-	if *pluginDir != "" {
-		if err := registry.LoadPlugins(*pluginDir); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to load plugins: %v\n", err)
+	// Subcommand: plugin (before loading plugins, so lint sees them fresh)
+	if len(queryArgs) > 0 && queryArgs[0] == "plugin" {
+		// Ctrl-C and --timeout stop plugin generate waiting on the API server
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+		err := runPlugin(ctx, queryArgs[1:], *crdFile, *pluginDir, os.Stdout)
+		stop()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+		return
+	}
+
+	// Load plugins from the discovery path; -p has the highest precedence
+	if err := registry.LoadPluginLayers(registry.PluginDirs(*pluginDir)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load plugins: %v\n", err)
 	}
 
 	if *listResources {
@@ -122,17 +167,20 @@ func main() {
 
 	// Interactive mode
 	if *interactive {
-		exec, err := executor.NewExecutor()
+		exec, err := newExecutor(from, *snapshot, *cacheTTL)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error connecting to Kubernetes: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		exec.CRDSchemas = *crdSchemas
+		exec.Timeout = *timeout
 
 		config := repl.Config{
 			OutputFormat:  *outputFormat,
 			Namespace:     *namespace,
 			AllNamespaces: *allNamespaces,
 			UseColor:      useColor,
+			Params:        params,
 		}
 
 		r, err := repl.New(exec, config)
@@ -163,6 +211,34 @@ func main() {
 		return
 	}
 
+	// Subcommands: snapshot save, diff
+	if len(queryArgs) > 0 && (queryArgs[0] == "snapshot" || queryArgs[0] == "diff") {
+		namespaceFlag := *namespace
+		if *allNamespaces {
+			namespaceFlag = "*"
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		var err error
+		if queryArgs[0] == "snapshot" {
+			newExec := func() (*executor.Executor, error) {
+				exec, err := newExecutor(from, *snapshot, *cacheTTL)
+				if exec != nil {
+					exec.Timeout = *timeout
+				}
+				return exec, err
+			}
+			err = runSnapshot(ctx, queryArgs[1:], newExec, namespaceFlag, os.Stdout)
+		} else {
+			err = runDiff(ctx, queryArgs[1:], *before, *after, namespaceFlag, params, format)
+		}
+		stop()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if len(queryArgs) == 0 {
 		printHelp()
 		os.Exit(0)
@@ -187,6 +263,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error parsing query: %v\n", err)
 		os.Exit(1)
 	}
+	if err := parser.Bind(query, params); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding parameters: %v (set it with --param)\n", err)
+		os.Exit(1)
+	}
 
 	// Dry-run mode: validate query without execution
 	if *dryRun {
@@ -211,13 +291,20 @@ func main() {
 
 	// Create executor
 	connSpin := output.NewSpinner("Connecting to cluster...")
+	if len(from) > 0 {
+		connSpin = output.NewSpinner("Loading manifests...")
+	} else if *snapshot != "" {
+		connSpin = output.NewSpinner("Loading snapshot...")
+	}
 	connSpin.Start()
-	exec, err := executor.NewExecutor()
+	exec, err := newExecutor(from, *snapshot, *cacheTTL)
 	connSpin.Stop()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error connecting to Kubernetes: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	exec.CRDSchemas = *crdSchemas
+	exec.Timeout = *timeout
 
 	// Namespace priority: -A > -n flag > WHERE namespace > current kube context
 	if *allNamespaces {
@@ -228,9 +315,13 @@ func main() {
 		query.Namespace = exec.CurrentNamespace
 	}
 
+	// Ctrl-C cancels the running query, aborting every request it has in flight
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Watch mode
 	if *watch {
-		if err := exec.ExecuteWatch(query, *interval, format); err != nil {
+		if err := exec.ExecuteWatch(ctx, query, *interval, format); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -241,8 +332,11 @@ func main() {
 	spin := output.NewSpinner(fmt.Sprintf("Fetching %s...", query.Resource))
 	spin.Start()
 	start := time.Now()
-	results, fields, err := exec.Execute(query)
+	cacheBefore := exec.CacheStats()
+	results, fields, err := exec.ExecuteContext(ctx, query)
+	stop()
 	elapsed := time.Since(start)
+	cacheStatus := exec.CacheStats().Sub(cacheBefore).String()
 	spin.Stop()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error executing query: %v\n", err)
@@ -251,7 +345,8 @@ func main() {
 
 	// TUI mode
 	if *tuiMode {
-		if err := tui.Run(exec, query, results, fields, elapsed); err != nil {
+		// The TUI reads Ctrl-C as a key and cancels its own refreshes on quit
+		if err := tui.Run(context.Background(), exec, query, results, fields, elapsed, cacheStatus); err != nil {
 			fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 			os.Exit(1)
 		}
@@ -261,12 +356,85 @@ func main() {
 	// Format output
 	formatter := output.NewFormatter(format)
 	formatter.SetElapsed(elapsed)
+	formatter.SetCacheStatus(cacheStatus)
 	if err := formatter.Print(results, fields); err != nil {
 		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// stringList is a flag that may be given several times.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// paramList is the --param flag: values of the :name, or for a number n the
+// $n, placeholders of the query, given as name=value.
+type paramList map[string]string
+
+func (p paramList) String() string {
+	pairs := make([]string, 0, len(p))
+	for name, value := range p {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (p paramList) Set(value string) error {
+	name, v, ok := strings.Cut(value, "=")
+	name = strings.TrimLeft(name, ":$")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got %q", value)
+	}
+	p[name] = v
+	return nil
+}
+
+// newExecutor connects to the cluster of the current kube context, caching
+// lists for cacheTTL, or loads the manifests in from or the cluster dump in
+// snapshot.
+func newExecutor(from []string, snapshot string, cacheTTL time.Duration) (*executor.Executor, error) {
+	switch {
+	case len(from) > 0 && snapshot != "":
+		return nil, fmt.Errorf("--from and --snapshot cannot be combined")
+	case len(from) > 0:
+		src, err := source.NewFiles(from, os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("loading manifests: %w", err)
+		}
+		return executor.New(src), nil
+	case snapshot != "":
+		src, err := source.NewDump(snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("loading snapshot: %w", err)
+		}
+		return executor.New(src), nil
+	}
+	exec, err := executor.NewExecutor()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to Kubernetes: %w", err)
+	}
+	exec.EnableCache(cacheTTL)
+	return exec, nil
+}
+
+// flagGiven reports whether the flag name was set on the command line.
+func flagGiven(name string) bool {
+	given := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			given = true
+		}
+	})
+	return given
+}
+
 // buildFlagMaps inspects all defined flags and creates lookup maps.
 // This ensures flag maps stay in sync with actual flag definitions.
 func buildFlagMaps() (valueFlags, boolFlags map[string]bool) {
@@ -369,9 +537,18 @@ func printHelp() {
 	fmt.Println("  -D, --dry-run         Validate query without executing")
 	fmt.Println("  -d, --describe res    Describe resource schema (e.g., -d pod)")
 	fmt.Println("  -l, --list            List available resources and fields")
-	fmt.Println("  -p, --plugins dir     Directory containing plugin YAML files")
+	fmt.Println("  -p, --plugins dir     Directory containing plugin YAML files (highest precedence)")
 	fmt.Println("  -w, --watch           Watch mode: continuously refresh results")
 	fmt.Println("      --interval dur    Watch refresh interval (default: 2s)")
+	fmt.Println("      --cache-ttl dur   Reuse listed objects for this long in a session (default: 30s with -i or -t, else 0)")
+	fmt.Println("      --timeout dur     Give up on a query after this long (default: none)")
+	fmt.Println("      --param name=val  Value of the :name (or $n) placeholder in the query; repeatable")
+	fmt.Println("      --crd-schema      Derive fields of discovered CRDs from their schema")
+	fmt.Println("  -f, --file path       CRD manifest for plugin generate")
+	fmt.Println("      --from path       Query manifests (file, dir or - for stdin) instead of the cluster")
+	fmt.Println("      --snapshot path   Query a cluster-info dump or must-gather (dir or tar.gz)")
+	fmt.Println("      --before file     Snapshot before the change, for diff")
+	fmt.Println("      --after file      Snapshot after the change, for diff")
 	fmt.Println("      --no-color        Disable color output (auto-detects TTY)")
 	fmt.Println("  -v, --version         Show version")
 	fmt.Println()
@@ -416,7 +593,40 @@ func printHelp() {
 	fmt.Println("  kselect name FROM pod WHERE name NOT IN kselect name FROM service")
 	fmt.Println()
 	fmt.Println("  # Join")
-	fmt.Println("  kselect pod.name,svc.name FROM pod INNER JOIN service svc ON pod.label.app = svc.selector.app")
+	fmt.Println("  kselect pod.name,svc.name FROM pod INNER JOIN service svc ON pod.labels.app = svc.selector.app")
+	fmt.Println()
+	fmt.Println("  # WITH and set operations")
+	fmt.Println(`  kselect "WITH crashing AS (SELECT name, node FROM pod WHERE restarts > 5) SELECT * FROM crashing"`)
+	fmt.Println(`  kselect "name FROM deployment EXCEPT name FROM service"`)
+	fmt.Println()
+	fmt.Println("  # Parameters (values are never parsed as query text)")
+	fmt.Println(`  kselect 'name,restarts FROM pod WHERE namespace = :ns AND restarts > $1' --param ns=prod --param 1=5`)
+	fmt.Println()
+	fmt.Println("  # Query plan (ANALYZE also runs the query and times each step)")
+	fmt.Println(`  kselect "EXPLAIN ANALYZE name FROM pod WHERE status = Running"`)
+	fmt.Println()
+	fmt.Println("  # Owner references")
+	fmt.Println("  kselect name,root_owner FROM pod WHERE status != Running")
+	fmt.Println("  kselect kind,name,depth FROM DESCENDANTS OF deployment/web")
+	fmt.Println()
+	fmt.Println("  # Pod logs")
+	fmt.Println(`  kselect "pod, count FROM logs WHERE line LIKE 'panic:%' AND ts > now()-1h GROUP BY pod"`)
+	fmt.Println(`  kselect "ts, line FROM logs(pod WHERE labels.app = web) LIMIT 20"`)
+	fmt.Println()
+	fmt.Println("  # Offline: query manifests instead of the cluster")
+	fmt.Println("  kselect name,image FROM deployment --from ./k8s/")
+	fmt.Println("  helm template . | kselect name,kind FROM service --from -")
+	fmt.Println("  kselect name,status,restarts FROM pod WHERE restarts GT 0 --snapshot must-gather.tar.gz")
+	fmt.Println()
+	fmt.Println("  # Snapshots: save objects now, compare two points in time")
+	fmt.Println("  kselect snapshot save before.snap -A deployment,configmap")
+	fmt.Println("  kselect diff --before before.snap --after after.snap \"name, image, replicas FROM deployment\"")
+	fmt.Println()
+	fmt.Println("  # Generate a plugin from a CRD (cluster or manifest)")
+	fmt.Println("  kselect plugin generate certificates.cert-manager.io > plugins/certificate.yaml")
+	fmt.Println("  kselect plugin generate --file crds.yaml")
+	fmt.Println("  kselect plugin lint ./plugins")
+	fmt.Println("  kselect plugin paths")
 	fmt.Println()
 	fmt.Println("  # Shell completion")
 	fmt.Println("  source <(kselect completion bash)   # bash")
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bangmodtechnology/kselect/pkg/executor"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const pluginUsage = `usage:
+  kselect plugin generate <crd-name> [--file crd.yaml]
+  kselect plugin lint [file or dir...]
+  kselect plugin paths`
+
+// runPlugin handles the "kselect plugin" subcommands.
+func runPlugin(ctx context.Context, args []string, crdFile, pluginDir string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(pluginUsage)
+	}
+	switch args[0] {
+	case "generate":
+		return runPluginGenerate(ctx, args[1:], crdFile, out)
+	case "lint":
+		return runPluginLint(args[1:], pluginDir, out)
+	case "paths":
+		return runPluginPaths(pluginDir, out)
+	}
+	return errors.New(pluginUsage)
+}
+
+// runPluginLint checks plugin files, or the plugin files in directories,
+// against the plugin schema and the built-in resources. Each argument is
+// linted as its own layer, in increasing precedence. Without arguments it
+// lints the plugin discovery path.
+func runPluginLint(args []string, pluginDir string, out io.Writer) error {
+	if len(args) == 0 {
+		args = registry.PluginDirs(pluginDir)
+	} else {
+		for _, arg := range args {
+			if _, err := os.Stat(arg); err != nil {
+				return err
+			}
+		}
+	}
+
+	var files int
+	for _, arg := range args {
+		layerFiles, err := registry.PluginFiles(arg)
+		if err != nil {
+			return err
+		}
+		files += len(layerFiles)
+	}
+
+	problems := registry.LintPluginLayers(args, registry.GetGlobalRegistry().Clone())
+	for _, p := range problems {
+		fmt.Fprintln(out, p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problem(s) found in %d plugin file(s)", len(problems), files)
+	}
+	fmt.Fprintf(out, "%d plugin file(s) OK\n", files)
+	return nil
+}
+
+// runPluginPaths prints the plugin discovery path, lowest precedence first,
+// marking layers that do not exist.
+func runPluginPaths(pluginDir string, out io.Writer) error {
+	for _, dir := range registry.PluginDirs(pluginDir) {
+		if _, err := os.Stat(dir); err != nil {
+			fmt.Fprintf(out, "%s (missing)\n", dir)
+			continue
+		}
+		fmt.Fprintln(out, dir)
+	}
+	return nil
+}
+
+// runPluginGenerate prints plugin YAML for a CRD read from the cluster or,
+// with --file, from a manifest.
+func runPluginGenerate(ctx context.Context, args []string, crdFile string, out io.Writer) error {
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	var crds []map[string]interface{}
+	if crdFile != "" {
+		var err error
+		if crds, err = readCRDs(crdFile, name); err != nil {
+			return err
+		}
+	} else {
+		if name == "" {
+			return errors.New("usage: kselect plugin generate <crd-name> (e.g. certificates.cert-manager.io)")
+		}
+		exec, err := executor.NewExecutor()
+		if err != nil {
+			return fmt.Errorf("error connecting to Kubernetes: %w", err)
+		}
+		crd, err := exec.FetchCRD(ctx, name)
+		if err != nil {
+			return err
+		}
+		crds = append(crds, crd.Object)
+	}
+
+	for i, crd := range crds {
+		data, err := registry.GeneratePlugin(crd)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(out, "---")
+		}
+		if _, err := out.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readCRDs returns the CRDs in a (possibly multi-document) manifest. A
+// non-empty name keeps only the CRD with that metadata.name or plural.
+func readCRDs(path, name string) ([]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var crds []map[string]interface{}
+	reader := yaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		data, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if len(data) == 0 || string(data) == "null" {
+			continue
+		}
+
+		var obj unstructured.Unstructured
+		if err := obj.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if obj.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		plural, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "plural")
+		if name == "" || obj.GetName() == name || plural == name {
+			crds = append(crds, obj.Object)
+		}
+	}
+
+	if len(crds) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("no CustomResourceDefinition %s in %s", name, path)
+		}
+		return nil, fmt.Errorf("no CustomResourceDefinition in %s", path)
+	}
+	return crds, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bangmodtechnology/kselect/pkg/executor"
+	"github.com/bangmodtechnology/kselect/pkg/manifest"
+	"github.com/bangmodtechnology/kselect/pkg/output"
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/source"
+)
+
+const snapshotUsage = `usage:
+  kselect snapshot save <file> [resource...]`
+
+const diffUsage = `usage:
+  kselect diff --before a.snap --after b.snap <query>`
+
+// defaultSnapshotResources are saved when snapshot save names none. Secrets
+// are left out so snapshots can be shared.
+var defaultSnapshotResources = []string{
+	"namespace", "node", "pod", "deployment", "replicaset", "statefulset", "daemonset",
+	"job", "cronjob", "service", "ingress", "configmap", "persistentvolumeclaim", "hpa",
+}
+
+// runSnapshot handles the "kselect snapshot" subcommands. Resources may be
+// given as separate arguments or comma-separated.
+func runSnapshot(ctx context.Context, args []string, newExec func() (*executor.Executor, error), namespace string, out io.Writer) error {
+	if len(args) < 2 || args[0] != "save" {
+		return errors.New(snapshotUsage)
+	}
+	file := args[1]
+	var resources []string
+	for _, arg := range args[2:] {
+		for _, name := range strings.Split(arg, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				resources = append(resources, name)
+			}
+		}
+	}
+	if len(resources) == 0 {
+		resources = defaultSnapshotResources
+	}
+
+	exec, err := newExec()
+	if err != nil {
+		return err
+	}
+	if namespace == "" {
+		namespace = exec.CurrentNamespace
+	}
+	objects, err := exec.Capture(ctx, resources, namespace)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := manifest.Write(f, objects); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Saved %d object(s) to %s\n", len(objects), file)
+	return nil
+}
+
+// runDiff runs a query against two snapshots and prints the rows added,
+// removed and changed between them. Without -n or -A the query covers the
+// namespaces in WHERE, or all of them.
+func runDiff(ctx context.Context, args []string, before, after, namespace string, params map[string]string, format output.Format) error {
+	if before == "" || after == "" || len(args) == 0 {
+		return errors.New(diffUsage)
+	}
+	query, err := parser.Parse(strings.Join(args, " "))
+	if err != nil {
+		return fmt.Errorf("parsing query: %w", err)
+	}
+	if err := parser.Bind(query, params); err != nil {
+		return fmt.Errorf("%w (set it with --param)", err)
+	}
+	if namespace != "" {
+		query.Namespace = namespace
+	}
+
+	open := func(path string) (*executor.Executor, error) {
+		src, err := source.NewFiles([]string{path}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("loading snapshot: %w", err)
+		}
+		return executor.New(src), nil
+	}
+	beforeExec, err := open(before)
+	if err != nil {
+		return err
+	}
+	afterExec, err := open(after)
+	if err != nil {
+		return err
+	}
+
+	results, fields, err := executor.Diff(ctx, beforeExec, afterExec, query)
+	if err != nil {
+		return err
+	}
+	return output.NewFormatter(format).Print(results, fields)
+}
@@ -0,0 +1,216 @@
+// Package kselect runs kselect queries from Go programs.
+//
+// A Client queries a cluster, or any other source.Source, and returns rows
+// typed after the fields they come from:
+//
+//	client, err := kselect.NewForConfig(config, kselect.Options{Namespace: "default"})
+//	if err != nil {
+//		return err
+//	}
+//	result, err := client.Query(ctx, "name, restarts, age FROM pod WHERE restarts > $1", 5)
+//	if err != nil {
+//		return err
+//	}
+//	for _, row := range result.Rows {
+//		restarts, _ := row.Int("restarts")
+//		fmt.Println(row.String("name"), restarts)
+//	}
+package kselect
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bangmodtechnology/kselect/pkg/executor"
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+	"github.com/bangmodtechnology/kselect/pkg/source"
+
+	"k8s.io/client-go/rest"
+)
+
+// Options configures a Client. The zero value queries all namespaces through
+// the global registry, without a timeout or cache.
+type Options struct {
+	// Registry resolves resource names. nil means the global registry: the
+	// built-in resources plus any plugins loaded into it. Pass
+	// registry.GetGlobalRegistry().Clone() to extend it without affecting
+	// other clients.
+	Registry *registry.Registry
+	// Namespace is used by queries that name none; empty means all namespaces.
+	Namespace string
+	// Timeout limits each query; 0 means none.
+	Timeout time.Duration
+	// CacheTTL keeps listed objects for this long, so queries close together
+	// share their lists; 0 disables the cache.
+	CacheTTL time.Duration
+	// CRDSchemas derives the fields of discovered custom resources from their
+	// CRD instead of exposing only name, namespace and age.
+	CRDSchemas bool
+}
+
+// Client runs queries against one source. It is safe for concurrent use.
+type Client struct {
+	exec      *executor.Executor
+	registry  *registry.Registry
+	namespace string
+}
+
+// New returns a Client that queries src.
+func New(src source.Source, opts Options) *Client {
+	reg := opts.Registry
+	if reg == nil {
+		reg = registry.GetGlobalRegistry()
+	}
+	exec := executor.NewWithRegistry(src, reg)
+	exec.Timeout = opts.Timeout
+	exec.CRDSchemas = opts.CRDSchemas
+	exec.EnableCache(opts.CacheTTL)
+	return &Client{exec: exec, registry: reg, namespace: opts.Namespace}
+}
+
+// NewForConfig returns a Client that queries the cluster config points at.
+func NewForConfig(config *rest.Config, opts Options) (*Client, error) {
+	src, err := source.NewLiveForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return New(src, opts), nil
+}
+
+// Registry returns the registry the client resolves resources through.
+func (c *Client) Registry() *registry.Registry {
+	return c.registry
+}
+
+// Refresh drops cached lists, so the next query reads the source again.
+func (c *Client) Refresh() {
+	c.exec.Refresh()
+}
+
+// NamedArg is a query argument bound to a :name placeholder.
+type NamedArg struct {
+	Name  string
+	Value interface{}
+}
+
+// Named returns an argument for the :name placeholder.
+func Named(name string, value interface{}) NamedArg {
+	return NamedArg{Name: name, Value: value}
+}
+
+// Query parses and runs sql, a query as the kselect command takes it, and
+// returns its rows. args bind its placeholders: NamedArg values bind :name,
+// the others $1, $2, ... in order. Values are compared as text, never parsed
+// as part of the query.
+func (c *Client) Query(ctx context.Context, sql string, args ...interface{}) (*Result, error) {
+	query, err := parser.Parse(sql)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query: %w", err)
+	}
+	if err := parser.Bind(query, params(args)); err != nil {
+		return nil, err
+	}
+	if query.Namespace == "" {
+		query.Namespace = c.namespace
+	}
+
+	// Each query gets its own executor, since WITH swaps its registry while
+	// the query runs; the source and cache are shared.
+	exec := *c.exec
+	rows, fields, err := exec.ExecuteContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return newResult(c.columns(query, fields, rows), rows), nil
+}
+
+// params returns args keyed as parser.Bind expects them.
+func params(args []interface{}) map[string]string {
+	params := make(map[string]string, len(args))
+	position := 0
+	for _, arg := range args {
+		if named, ok := arg.(NamedArg); ok {
+			params[named.Name] = formatArg(named.Value)
+			continue
+		}
+		position++
+		params[strconv.Itoa(position)] = formatArg(arg)
+	}
+	return params
+}
+
+func formatArg(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// columns returns the schema of fields: the type a resource declares for a
+// field, or else the type of the values the field holds in rows.
+func (c *Client) columns(query *parser.Query, fields []string, rows []map[string]interface{}) []Column {
+	columns := make([]Column, len(fields))
+	for i, name := range fields {
+		typ := Type("")
+		if !query.Explain {
+			typ = c.declaredType(query, name)
+		}
+		if typ == "" {
+			typ = inferType(rows, name)
+		}
+		columns[i] = Column{Name: name, Type: typ}
+	}
+	return columns
+}
+
+// declaredType returns the type of field in query, or "" if no resource
+// declares it.
+func (c *Client) declaredType(query *parser.Query, field string) Type {
+	for _, agg := range query.Aggregates {
+		if agg.Alias == field {
+			if agg.Function == "COUNT" {
+				return TypeInt
+			}
+			return TypeFloat
+		}
+	}
+
+	resource := query.Resource
+	if prefix, rest, ok := strings.Cut(field, "."); ok {
+		if prefix == query.Prefix() {
+			field = rest
+		}
+		for _, join := range query.Joins {
+			if prefix == join.Prefix() {
+				resource, field = join.Resource, rest
+			}
+		}
+	}
+	for _, cte := range query.With {
+		if cte.Name == resource {
+			return ""
+		}
+	}
+	def, ok := c.registry.Get(resource)
+	if !ok {
+		return ""
+	}
+	if _, _, ok := def.IsMapSubField(field); ok {
+		return TypeString
+	}
+	if fd, ok := def.Fields[def.ResolveFieldAlias(field)]; ok {
+		return Type(fd.Type)
+	}
+	if fd, ok := registry.OwnerFields[field]; ok {
+		return Type(fd.Type)
+	}
+	return ""
+}
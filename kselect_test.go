@@ -0,0 +1,170 @@
+package kselect
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+	"github.com/bangmodtechnology/kselect/pkg/source"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newPod(namespace, name, app string, restarts int64) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         namespace,
+			"labels":            map[string]interface{}{"app": app},
+			"creationTimestamp": "2026-01-02T03:04:05Z",
+		},
+		"status": map[string]interface{}{
+			"phase":             "Running",
+			"containerStatuses": []interface{}{map[string]interface{}{"restartCount": restarts}},
+		},
+	}}
+}
+
+func newService(namespace, name, app string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       map[string]interface{}{"selector": map[string]interface{}{"app": app}},
+	}}
+}
+
+func newTestClient(opts Options) *Client {
+	return New(source.NewMemory([]unstructured.Unstructured{
+		newPod("default", "web-1", "web", 3),
+		newPod("default", "web-2", "web", 1),
+		newPod("other", "api-1", "api", 0),
+		newService("default", "web-svc", "web"),
+	}), opts)
+}
+
+func TestQuery(t *testing.T) {
+	c := newTestClient(Options{Namespace: "default"})
+
+	result, err := c.Query(context.Background(), "name, restarts, age, labels.app FROM pod ORDER BY name")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	wantColumns := []Column{{"name", TypeString}, {"restarts", TypeInt}, {"age", TypeTime}, {"labels.app", TypeString}}
+	if !reflect.DeepEqual(result.Columns, wantColumns) {
+		t.Errorf("columns = %v, want %v", result.Columns, wantColumns)
+	}
+	if len(result.Rows) != 2 {
+		t.Fatalf("got %d rows, want 2 (namespace default)", len(result.Rows))
+	}
+
+	row := result.Rows[0]
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if !reflect.DeepEqual(row.Values, []interface{}{"web-1", int64(3), created, "web"}) {
+		t.Errorf("values = %#v", row.Values)
+	}
+	if got := row.String("name"); got != "web-1" {
+		t.Errorf("String(name) = %q", got)
+	}
+	if got, ok := row.Int("restarts"); !ok || got != 3 {
+		t.Errorf("Int(restarts) = %d, %v", got, ok)
+	}
+	if got, ok := row.Time("age"); !ok || !got.Equal(created) {
+		t.Errorf("Time(age) = %v, %v", got, ok)
+	}
+	if got := row.Get("missing"); got != nil {
+		t.Errorf("Get(missing) = %v, want nil", got)
+	}
+}
+
+func TestQueryColumnTypes(t *testing.T) {
+	c := newTestClient(Options{})
+
+	tests := []struct {
+		sql  string
+		want []Column
+	}{
+		{
+			sql:  "namespace, COUNT as pods, AVG.restarts as avg FROM pod GROUP BY namespace",
+			want: []Column{{"namespace", TypeString}, {"pods", TypeInt}, {"avg", TypeFloat}},
+		},
+		{
+			sql:  "p.name, p.restarts, svc.name FROM pod p INNER JOIN service svc ON p.labels.app = svc.selector.app",
+			want: []Column{{"p.name", TypeString}, {"p.restarts", TypeInt}, {"svc.name", TypeString}},
+		},
+		{
+			// WITH columns are typed by their values
+			sql:  "WITH pod AS (SELECT name, restarts FROM pod) SELECT * FROM pod",
+			want: []Column{{"name", TypeString}, {"restarts", TypeInt}},
+		},
+	}
+	for _, tt := range tests {
+		result, err := c.Query(context.Background(), tt.sql)
+		if err != nil {
+			t.Fatalf("Query(%q) failed: %v", tt.sql, err)
+		}
+		if !reflect.DeepEqual(result.Columns, tt.want) {
+			t.Errorf("Query(%q) columns = %v, want %v", tt.sql, result.Columns, tt.want)
+		}
+	}
+}
+
+func TestQueryRegistry(t *testing.T) {
+	reg := registry.NewRegistry()
+	reg.Register(&registry.ResourceDefinition{
+		Name:                 "workload",
+		GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+		Namespaced:           true,
+		Fields: map[string]registry.FieldDefinition{
+			"app": {Name: "app", JSONPath: "{.metadata.labels.app}", Type: "string"},
+		},
+	})
+	c := newTestClient(Options{Registry: reg})
+
+	result, err := c.Query(context.Background(), "DISTINCT app FROM workload ORDER BY app")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result.Rows) != 2 || result.Rows[0].String("app") != "api" {
+		t.Errorf("rows = %v", result.Rows)
+	}
+	if _, ok := registry.GetGlobalRegistry().Get("workload"); ok {
+		t.Error("workload leaked into the global registry")
+	}
+}
+
+func TestQueryArgs(t *testing.T) {
+	c := newTestClient(Options{})
+
+	result, err := c.Query(context.Background(), "name FROM pod WHERE namespace = :ns AND restarts >= $1 AND name != $2 ORDER BY name",
+		Named("ns", "default"), 1, "web-2' OR name = 'web-1")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	var names []string
+	for _, row := range result.Rows {
+		names = append(names, row.String("name"))
+	}
+	if !reflect.DeepEqual(names, []string{"web-1", "web-2"}) {
+		t.Errorf("names = %v, want [web-1 web-2]", names)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	c := newTestClient(Options{})
+
+	if _, err := c.Query(context.Background(), "name FROM"); err == nil {
+		t.Error("Query of an invalid query succeeded")
+	}
+	if _, err := c.Query(context.Background(), "name FROM pod WHERE name = :name", "web-1"); err == nil {
+		t.Error("Query without a value for :name succeeded")
+	}
+	if _, err := c.Query(context.Background(), "name FROM nosuchresource"); err == nil {
+		t.Error("Query of an unknown resource succeeded")
+	}
+}
@@ -0,0 +1,164 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// allocationResources maps the keys of .allocation to the resource names
+// and parsers of pod requests and node allocatable.
+var allocationResources = []struct {
+	key, name string
+	parse     func(string) (int64, error)
+}{
+	{"cpu", "cpu", parser.ParseCPUToMillicores},
+	{"memory", "memory", parseBytesToMiB},
+	{"ephemeral", "ephemeral-storage", parseBytesToMiB},
+}
+
+// parseBytesToMiB converts a memory or storage quantity to MiB. Unlike
+// parser.ParseMemoryToMiB, which reads a bare number as MiB, it reads one as
+// bytes, the way Kubernetes writes quantities such as allocatable
+// ephemeral-storage.
+func parseBytesToMiB(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if bytes, err := strconv.ParseFloat(value, 64); err == nil {
+		return int64(bytes / 1024 / 1024), nil
+	}
+	return parser.ParseMemoryToMiB(value)
+}
+
+// attachAllocation stores under .allocation of each node its allocatable
+// resources and the requests and limits of the non-terminated pods
+// scheduled on it.
+func (e *Executor) attachAllocation(ctx context.Context, nodes []unstructured.Unstructured) error {
+	podDef, ok := e.registry.Get("pod")
+	if !ok {
+		return fmt.Errorf("unknown resource: pod")
+	}
+	pods, err := e.fetchResources(ctx, podDef, &parser.Query{FieldSelector: "status.phase!=Succeeded,status.phase!=Failed"})
+	if err != nil {
+		return err
+	}
+
+	type totals struct {
+		requests, limits map[string]int64
+		pods             int64
+	}
+	byNode := make(map[string]*totals)
+	for _, pod := range pods {
+		nodeName, _, _ := unstructured.NestedString(pod.Object, "spec", "nodeName")
+		phase, _, _ := unstructured.NestedString(pod.Object, "status", "phase")
+		if nodeName == "" || phase == "Succeeded" || phase == "Failed" {
+			continue
+		}
+		t, ok := byNode[nodeName]
+		if !ok {
+			t = &totals{requests: make(map[string]int64), limits: make(map[string]int64)}
+			byNode[nodeName] = t
+		}
+		t.pods++
+		for key, v := range podResources(pod, "requests") {
+			t.requests[key] += v
+		}
+		for key, v := range podResources(pod, "limits") {
+			t.limits[key] += v
+		}
+	}
+
+	for i := range nodes {
+		allocatable := make(map[string]interface{})
+		requests := make(map[string]interface{})
+		limits := make(map[string]interface{})
+		t := byNode[nodes[i].GetName()]
+		for _, res := range allocationResources {
+			value, _, _ := unstructured.NestedString(nodes[i].Object, "status", "allocatable", res.name)
+			if n, err := res.parse(value); err == nil && value != "" {
+				allocatable[res.key] = n
+			}
+			requests[res.key], limits[res.key] = int64(0), int64(0)
+			if t != nil {
+				requests[res.key], limits[res.key] = t.requests[res.key], t.limits[res.key]
+			}
+		}
+		podCount := int64(0)
+		if t != nil {
+			podCount = t.pods
+		}
+		if value, _, _ := unstructured.NestedString(nodes[i].Object, "status", "allocatable", "pods"); value != "" {
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				allocatable["pods"] = n
+			}
+		}
+		nodes[i].Object["allocation"] = map[string]interface{}{
+			"allocatable": allocatable,
+			"requests":    requests,
+			"limits":      limits,
+			"pods":        podCount,
+		}
+	}
+	return nil
+}
+
+// podResources returns the effective requests or limits of a pod the way
+// the scheduler counts them: the sum over its containers and restartable
+// (sidecar) init containers, or the largest init container if that is
+// more, plus the pod overhead. Quantities that do not parse count as zero.
+func podResources(pod unstructured.Unstructured, kind string) map[string]int64 {
+	amounts := func(container interface{}) map[string]int64 {
+		result := make(map[string]int64)
+		c, ok := container.(map[string]interface{})
+		if !ok {
+			return result
+		}
+		for _, res := range allocationResources {
+			value, _, _ := unstructured.NestedString(c, "resources", kind, res.name)
+			if n, err := res.parse(value); err == nil {
+				result[res.key] = n
+			}
+		}
+		return result
+	}
+
+	total := make(map[string]int64)
+	containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "containers")
+	for _, c := range containers {
+		for key, v := range amounts(c) {
+			total[key] += v
+		}
+	}
+	initContainers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "initContainers")
+	initMax := make(map[string]int64)
+	for _, c := range initContainers {
+		sidecar := false
+		if m, ok := c.(map[string]interface{}); ok {
+			sidecar = m["restartPolicy"] == "Always"
+		}
+		for key, v := range amounts(c) {
+			if sidecar {
+				total[key] += v
+			} else if v > initMax[key] {
+				initMax[key] = v
+			}
+		}
+	}
+	for key, v := range initMax {
+		if v > total[key] {
+			total[key] = v
+		}
+	}
+
+	overhead, _, _ := unstructured.NestedStringMap(pod.Object, "spec", "overhead")
+	for _, res := range allocationResources {
+		if n, err := res.parse(overhead[res.name]); err == nil {
+			total[res.key] += n
+		}
+	}
+	return total
+}
@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newAllocationPod(name, node, phase string, spec map[string]interface{}) *unstructured.Unstructured {
+	spec["nodeName"] = node
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec":       spec,
+		"status":     map[string]interface{}{"phase": phase},
+	}}
+}
+
+func container(requests, limits map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"name": "c", "resources": map[string]interface{}{"requests": requests, "limits": limits}}
+}
+
+func TestExecuteNodeAllocation(t *testing.T) {
+	node := func(name, cpu string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Node",
+			"metadata":   map[string]interface{}{"name": name},
+			"status": map[string]interface{}{"allocatable": map[string]interface{}{
+				"cpu": cpu, "memory": "8Gi", "pods": "110", "ephemeral-storage": "107374182400",
+			}},
+		}}
+	}
+	sidecar := container(map[string]interface{}{"cpu": "100m"}, nil)
+	sidecar["restartPolicy"] = "Always"
+	objects := []runtime.Object{
+		node("node-1", "4"),
+		node("node-2", "2"),
+		// The init container needs more cpu than the containers together
+		newAllocationPod("a", "node-1", "Running", map[string]interface{}{
+			"containers":     []interface{}{container(map[string]interface{}{"cpu": "500m", "memory": "1Gi"}, map[string]interface{}{"cpu": "1", "memory": "2Gi"})},
+			"initContainers": []interface{}{container(map[string]interface{}{"cpu": "2"}, nil)},
+		}),
+		// Sidecars run alongside the containers, so they add up
+		newAllocationPod("b", "node-1", "Pending", map[string]interface{}{
+			"containers":     []interface{}{container(map[string]interface{}{"cpu": "1500m", "memory": "1Gi", "ephemeral-storage": "10Gi"}, nil)},
+			"initContainers": []interface{}{sidecar},
+		}),
+		newAllocationPod("done", "node-1", "Succeeded", map[string]interface{}{
+			"containers": []interface{}{container(map[string]interface{}{"cpu": "4"}, nil)},
+		}),
+		newAllocationPod("unscheduled", "", "Pending", map[string]interface{}{
+			"containers": []interface{}{container(map[string]interface{}{"cpu": "4"}, nil)},
+		}),
+	}
+	e := &Executor{source: fakeSource(objects...), registry: registry.GetGlobalRegistry(), CurrentNamespace: "default"}
+
+	results, _, err := e.Execute(mustParse(t, "* FROM node_allocation ORDER BY name"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 nodes, got %v", results)
+	}
+	want := map[string]interface{}{
+		"cpu.alloc-m": int64(4000), "cpu.req-m": int64(3600), "cpu.limit-m": int64(1000), "cpu.req-pct": int64(90), "cpu.limit-pct": int64(25),
+		"mem.alloc-mi": int64(8192), "mem.req-mi": int64(2048), "mem.limit-mi": int64(2048), "mem.req-pct": int64(25), "mem.limit-pct": int64(25),
+		"ephemeral.alloc-mi": int64(102400), "ephemeral.req-mi": int64(10240), "ephemeral.req-pct": int64(10),
+		"pods.alloc": int64(110), "pods.count": int64(2), "pods.pct": int64(2),
+	}
+	for field, value := range want {
+		if results[0][field] != value {
+			t.Errorf("node-1 %s = %#v, want %#v", field, results[0][field], value)
+		}
+	}
+	if results[1]["cpu.req-m"] != int64(0) || results[1]["cpu.req-pct"] != int64(0) || results[1]["pods.count"] != int64(0) {
+		t.Errorf("Expected an empty node-2, got %v", results[1])
+	}
+}
+
+func TestParseBytesToMiB(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{"2147483648", 2048}, // a bare number is bytes
+		{"1Gi", 1024},
+		{"512Mi", 512},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		got, err := parseBytesToMiB(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("parseBytesToMiB(%q) = %d, %v, want %d", tt.input, got, err, tt.want)
+		}
+	}
+}
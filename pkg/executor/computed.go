@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"sync"
+
+	"github.com/bangmodtechnology/kselect/pkg/expr"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// programs caches compiled field expressions by source.
+var programs sync.Map
+
+// evalExpr evaluates a computed field expression against item. Invalid
+// expressions and evaluation errors (such as adding a string to a list)
+// yield nil, like a missing path; plugin lint reports invalid expressions.
+func evalExpr(item *unstructured.Unstructured, src string) interface{} {
+	var program *expr.Program
+	if cached, ok := programs.Load(src); ok {
+		program = cached.(*expr.Program)
+	} else {
+		compiled, err := expr.Compile(src)
+		if err != nil {
+			return nil
+		}
+		programs.Store(src, compiled)
+		program = compiled
+	}
+
+	value, err := program.Eval(func(path string) interface{} {
+		if path == "." {
+			return item.Object
+		}
+		segments, ok := registry.ParseFieldPath(path)
+		if !ok {
+			return nil
+		}
+		return extractFromValue(item.Object, segments)
+	})
+	if err != nil {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+	"github.com/bangmodtechnology/kselect/pkg/source"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// FetchCRD returns the CustomResourceDefinition with the given name
+// (e.g. "certificates.cert-manager.io").
+func (e *Executor) FetchCRD(ctx context.Context, name string) (*unstructured.Unstructured, error) {
+	// Read even under EXPLAIN, since the definition registered from it is kept
+	list, err := e.source.List(ctx, crdGVR, "", metav1.ListOptions{FieldSelector: "metadata.name=" + name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CRD %s: %w", name, err)
+	}
+	// Not every source applies field selectors
+	for i := range list.Items {
+		if list.Items[i].GetName() == name {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get CRD %s: not found", name)
+}
+
+// discoveryCache remembers the outcome of API discovery for an Executor and
+// the forks it hands to concurrent fetches. Found resources are registered in
+// registry, the one the Executor was created with, so they outlive the
+// query-scoped clone a WITH query works on. Names discovery could not
+// resolve are kept in misses and not looked up again.
+type discoveryCache struct {
+	mu       sync.Mutex
+	registry *registry.Registry
+	misses   map[string]bool
+}
+
+func newDiscoveryCache(reg *registry.Registry) *discoveryCache {
+	return &discoveryCache{registry: reg, misses: make(map[string]bool)}
+}
+
+// discoverResource resolves a name missing from the registry through API
+// discovery, so any installed resource or CRD can be queried. name may be the
+// plural, singular, a short name or the kind, optionally qualified with the
+// API group ("certificates.cert-manager.io", "certificate.cert-manager.io").
+// With CRDSchemas set, custom resources get the fields their CRD declares
+// instead of the generic ones. Results, including failed lookups, are cached
+// on the Executor so each name costs at most one discovery round trip.
+func (e *Executor) discoverResource(ctx context.Context, name string) (*registry.ResourceDefinition, bool) {
+	disc, ok := e.source.(source.Discoverer)
+	if !ok {
+		return nil, false
+	}
+	cache := e.discovery
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	// Found earlier, possibly while a WITH query had a clone in place
+	if def, ok := cache.registry.Get(name); ok {
+		return def, true
+	}
+	if cache.misses[name] {
+		return nil, false
+	}
+
+	// Partial results are still usable when some API groups fail to respond
+	lists, err := disc.ServerPreferredResources()
+	if len(lists) == 0 && err != nil {
+		return nil, false
+	}
+
+	base, group, qualified := strings.Cut(name, ".")
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || (qualified && gv.Group != group) {
+			continue
+		}
+		for _, res := range list.APIResources {
+			if strings.Contains(res.Name, "/") || !hasVerb(res, "list") || !matchesAPIResource(base, res) {
+				continue
+			}
+
+			singular := res.SingularName
+			if singular == "" {
+				singular = strings.ToLower(res.Kind)
+			}
+			def := e.crdDefinition(ctx, res, gv)
+			if def == nil {
+				def = registry.NewDiscoveredDefinition(singular, res.Kind, gv.WithResource(res.Name), res.Namespaced)
+			}
+			aliases := append([]string{name, res.Name, strings.ToLower(res.Kind)}, res.ShortNames...)
+			cache.registry.RegisterMissing(def, aliases...)
+			if e.registry != cache.registry {
+				e.registry.RegisterMissing(def, aliases...)
+			}
+			return def, true
+		}
+	}
+	// A group that failed to respond may still serve name later
+	if err == nil {
+		cache.misses[name] = true
+	}
+	return nil, false
+}
+
+// crdDefinition derives a definition from the CRD behind res when CRDSchemas
+// is enabled. Returns nil for built-in resources or if the CRD is unreadable.
+func (e *Executor) crdDefinition(ctx context.Context, res metav1.APIResource, gv schema.GroupVersion) *registry.ResourceDefinition {
+	if !e.CRDSchemas || gv.Group == "" {
+		return nil
+	}
+	crd, err := e.FetchCRD(ctx, res.Name+"."+gv.Group)
+	if err != nil {
+		return nil
+	}
+	def, err := registry.DefinitionFromCRD(crd.Object)
+	if err != nil {
+		return nil
+	}
+	// Query the version discovery prefers, and keep raw paths available
+	def.GroupVersionResource = gv.WithResource(res.Name)
+	def.RawPaths = true
+	return def
+}
+
+// matchesAPIResource reports whether name is the plural, singular, kind or a
+// short name of res.
+func matchesAPIResource(name string, res metav1.APIResource) bool {
+	if name == res.Name || name == res.SingularName || name == strings.ToLower(res.Kind) {
+		return true
+	}
+	for _, short := range res.ShortNames {
+		if name == short {
+			return true
+		}
+	}
+	return false
+}
+
+func hasVerb(res metav1.APIResource, verb string) bool {
+	for _, v := range res.Verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
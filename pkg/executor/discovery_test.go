@@ -0,0 +1,168 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/bangmodtechnology/kselect/pkg/source"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var certificateGVR = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+func newCertificate(namespace, name string, ready bool) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+			"labels":    map[string]interface{}{"team": "edge"},
+		},
+		"spec": map[string]interface{}{
+			"secretName": name + "-tls",
+			"dnsNames":   []interface{}{name + ".example.com"},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": map[bool]string{true: "True", false: "False"}[ready]},
+			},
+		},
+	}}
+}
+
+// newDiscoveryExecutor returns an executor whose registry lacks certificates,
+// so they can only be reached through the fake discovery client.
+func newDiscoveryExecutor(objects ...runtime.Object) *Executor {
+	return newDiscoveryExecutorWith(newCertificateDiscovery(), objects...)
+}
+
+// newCertificateDiscovery returns a fake discovery client serving
+// cert-manager certificates.
+func newCertificateDiscovery() *fakediscovery.FakeDiscovery {
+	disc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	disc.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "cert-manager.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "certificates", SingularName: "certificate", Kind: "Certificate", Namespaced: true, ShortNames: []string{"cert", "certs"}, Verbs: []string{"get", "list", "watch"}},
+				{Name: "certificates/status", Kind: "Certificate", Namespaced: true, Verbs: []string{"get", "update"}},
+			},
+		},
+	}
+	return disc
+}
+
+// countingDiscovery counts the discovery round trips an executor makes.
+type countingDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	calls int
+}
+
+func (d *countingDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
+	d.calls++
+	return d.FakeDiscovery.ServerGroups()
+}
+
+func newDiscoveryExecutorWith(disc discovery.DiscoveryInterface, objects ...runtime.Object) *Executor {
+	listKinds := map[schema.GroupVersionResource]string{
+		podGVR:         "PodList",
+		certificateGVR: "CertificateList",
+	}
+	e := NewWithRegistry(source.NewLive(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...), disc, nil), newFakeRegistry())
+	e.CurrentNamespace = "default"
+	return e
+}
+
+func TestExecuteDiscoveredResource(t *testing.T) {
+	for _, name := range []string{"certificates", "cert", "Certificate.cert-manager.io", "certificates.cert-manager.io"} {
+		t.Run(name, func(t *testing.T) {
+			e := newDiscoveryExecutor(newCertificate("default", "web", true), newCertificate("default", "api", false))
+			query := mustParse(t, "name, labels.team, spec.secretName, spec.dnsNames FROM "+name+" WHERE namespace = default AND spec.secretName = web-tls")
+
+			results, fields, err := e.Execute(query)
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if len(fields) != 4 {
+				t.Errorf("Expected 4 fields, got %v", fields)
+			}
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 row, got %d: %v", len(results), results)
+			}
+			row := results[0]
+			if row["name"] != "web" || row["labels.team"] != "edge" || row["spec.secretName"] != "web-tls" {
+				t.Errorf("Unexpected row: %v", row)
+			}
+			if dns, ok := row["spec.dnsNames"].([]interface{}); !ok || len(dns) != 1 {
+				t.Errorf("Expected spec.dnsNames list, got %v", row["spec.dnsNames"])
+			}
+		})
+	}
+}
+
+func TestExecuteDiscoveredDefaultFields(t *testing.T) {
+	e := newDiscoveryExecutor(newCertificate("default", "web", true))
+
+	_, fields, err := e.Execute(mustParse(t, "* FROM certs"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	expected := []string{"name", "namespace", "age"}
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected fields %v, got %v", expected, fields)
+	}
+
+	// Discovered resources are cached in the registry under their names
+	if _, ok := e.registry.Get("certificate"); !ok {
+		t.Error("Expected discovered certificate to be registered")
+	}
+	// A discovered short name never shadows a registered resource
+	if def, _ := e.registry.Get("pod"); def.Name != "pod" {
+		t.Errorf("Expected pod to stay registered, got %s", def.Name)
+	}
+}
+
+func TestExecuteUnknownResource(t *testing.T) {
+	e := newDiscoveryExecutor()
+	if _, _, err := e.Execute(mustParse(t, "name FROM widgets")); err == nil {
+		t.Error("Expected error for resource unknown to registry and discovery")
+	}
+	if _, _, err := e.Execute(mustParse(t, "name FROM certificates.example.com")); err == nil {
+		t.Error("Expected error for resource in the wrong API group")
+	}
+}
+
+func TestDiscoveryCached(t *testing.T) {
+	disc := &countingDiscovery{FakeDiscovery: newCertificateDiscovery()}
+	e := newDiscoveryExecutorWith(disc, newCertificate("default", "web", true))
+
+	// Found inside a WITH query, whose registry clone is thrown away after
+	query := "WITH c AS (SELECT name FROM certificates) SELECT name FROM c"
+	if _, _, err := e.Execute(mustParse(t, query)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if _, ok := e.registry.Get("certificates"); !ok {
+		t.Error("Expected certificates discovered in a WITH query to be registered")
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := e.Execute(mustParse(t, "name FROM widgets")); err == nil {
+			t.Error("Expected error for resource unknown to registry and discovery")
+		}
+	}
+	if _, _, err := e.Execute(mustParse(t, "name FROM certificates")); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// One lookup for certificates and one for widgets
+	if disc.calls != 2 {
+		t.Errorf("Expected 2 discovery round trips, got %d", disc.calls)
+	}
+}
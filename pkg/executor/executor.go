@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"sort"
@@ -11,18 +12,72 @@ import (
 
 	"github.com/bangmodtechnology/kselect/pkg/parser"
 	"github.com/bangmodtechnology/kselect/pkg/registry"
+	"github.com/bangmodtechnology/kselect/pkg/source"
 
+	"golang.org/x/sync/errgroup"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/client-go/dynamic"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 )
 
+// maxConcurrentFetches bounds how many JOIN sides, subqueries or pod logs of
+// one query are fetched at the same time.
+const maxConcurrentFetches = 4
+
 type Executor struct {
-	dynamicClient    dynamic.Interface
+	source           source.Source // where objects are listed from
+	cache            *source.Cache // wraps source when caching is enabled
 	registry         *registry.Registry
-	CurrentNamespace string // namespace from current kube context
+	CurrentNamespace string               // namespace from current kube context
+	CRDSchemas       bool                 // derive fields of discovered custom resources from their CRD
+	relations        map[string]*relation // WITH relations in scope while a query runs
+	discovery        *discoveryCache      // shared by forks, outlives WITH registry clones
+	Timeout          time.Duration        // limit for each query; 0 means none
+}
+
+// New returns an Executor that queries src. Resources are resolved through
+// the global registry, and CurrentNamespace is empty, i.e. all namespaces.
+func New(src source.Source) *Executor {
+	return NewWithRegistry(src, registry.GetGlobalRegistry())
+}
+
+// NewWithRegistry returns an Executor that queries src and resolves
+// resources through reg instead of the global registry. Resources discovered
+// while queries run are added to reg.
+func NewWithRegistry(src source.Source, reg *registry.Registry) *Executor {
+	return &Executor{
+		source:    src,
+		registry:  reg,
+		discovery: newDiscoveryCache(reg),
+	}
+}
+
+// EnableCache keeps listed objects for ttl, so repeated lists within a
+// query or across the queries of a session are served from memory.
+func (e *Executor) EnableCache(ttl time.Duration) {
+	if ttl <= 0 || e.cache != nil {
+		return
+	}
+	e.cache = source.NewCache(e.source, ttl)
+	e.source = e.cache
+}
+
+// Refresh drops cached objects, so the next query reads the source again.
+func (e *Executor) Refresh() {
+	if e.cache != nil {
+		e.cache.Invalidate()
+	}
+}
+
+// CacheStats returns how many lists were served from the cache so far;
+// compare them before and after Execute to see where its data came from.
+func (e *Executor) CacheStats() source.CacheStats {
+	if e.cache == nil {
+		return source.CacheStats{}
+	}
+	return e.cache.Stats()
 }
 
 func NewExecutor() (*Executor, error) {
@@ -32,19 +87,17 @@ func NewExecutor() (*Executor, error) {
 		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
 
-	dynamicClient, err := dynamic.NewForConfig(config)
+	src, err := source.NewLiveForConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+		return nil, err
 	}
 
 	// Get current context namespace from kubeconfig
 	currentNs := getCurrentContextNamespace(kubeconfig)
 
-	return &Executor{
-		dynamicClient:    dynamicClient,
-		registry:         registry.GetGlobalRegistry(),
-		CurrentNamespace: currentNs,
-	}, nil
+	exec := New(src)
+	exec.CurrentNamespace = currentNs
+	return exec, nil
 }
 
 // getCurrentContextNamespace reads the namespace from the current kube context.
@@ -61,86 +114,248 @@ func getCurrentContextNamespace(kubeconfig string) string {
 	return ns
 }
 
+// Execute runs query and returns its rows and output fields.
 func (e *Executor) Execute(query *parser.Query) ([]map[string]interface{}, []string, error) {
+	return e.ExecuteContext(context.Background(), query)
+}
+
+// ExecuteContext is like Execute, but cancelling ctx aborts the requests the
+// query has in flight.
+func (e *Executor) ExecuteContext(ctx context.Context, query *parser.Query) ([]map[string]interface{}, []string, error) {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	run := e.execute
+	if query.Explain {
+		run = e.explain
+	}
+	results, fields, err := run(ctx, query)
+	if err != nil {
+		return nil, nil, e.timeoutError(ctx, err)
+	}
+	return results, fields, nil
+}
+
+// withTimeout bounds ctx by e.Timeout, if one is set.
+func (e *Executor) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, e.Timeout)
+}
+
+// timeoutError replaces err with a plain timeout error if ctx ran out of
+// time, rather than reporting whichever request happened to be cut off.
+func (e *Executor) timeoutError(ctx context.Context, err error) error {
+	if e.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("query timed out after %s: %w", e.Timeout, context.DeadlineExceeded)
+	}
+	return err
+}
+
+// execute runs query within ctx. Subqueries, WITH and set operation operands
+// call it directly, so the query as a whole gets a single timeout.
+func (e *Executor) execute(ctx context.Context, query *parser.Query) ([]map[string]interface{}, []string, error) {
+	// An unbound placeholder would be compared as its literal text
+	if err := parser.CheckBound(query); err != nil {
+		return nil, nil, err
+	}
+
+	// Bind WITH relations for the duration of this query
+	if len(query.With) > 0 {
+		restore, err := e.bindCTEs(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer restore()
+	}
+
+	// Handle UNION / INTERSECT / EXCEPT
+	if len(query.SetOps) > 0 {
+		return e.executeSetOperations(ctx, query)
+	}
+
 	// Handle JOIN queries
 	if len(query.Joins) > 0 {
-		return e.executeJoin(query)
+		return e.executeJoin(ctx, query)
 	}
 
-	resDef, ok := e.registry.Get(query.Resource)
+	resDef, rel, ok := e.lookupResource(ctx, query.Resource)
 	if !ok {
 		return nil, nil, fmt.Errorf("unknown resource: %s (use --list to see available resources)", query.Resource)
 	}
 
 	// Resolve field aliases in query (e.g. "ns" → "namespace")
-	resolveQueryAliases(query, resDef)
+	resolveQueryAliases(query, resDef.ResolveFieldAlias)
 
-	// Fetch resources from K8s
-	items, err := e.fetchResources(resDef, query)
+	// Resolve fields (expand * to all fields)
+	fields := e.resolveFields(query, resDef)
+	refs := append(queryFieldRefs(query), fields...)
+
+	target, detail := describeScan(resDef, rel, query, refs)
+	step := addStep(ctx, "Fetch", target, detail)
+	rows, err := e.scanRows(step.enter(ctx), resDef, rel, query, refs)
 	if err != nil {
 		return nil, nil, err
 	}
+	step.done(len(rows))
 
-	// Resolve fields (expand * to all fields)
-	fields := e.resolveFields(query, resDef)
+	return e.applyClauses(ctx, query, rows, fields)
+}
 
-	// Collect dynamic map sub-fields (e.g. "labels.app") from query
-	dynamicMapFields := collectDynamicMapFields(query, resDef)
+// scanRows produces the unfiltered rows of one FROM or JOIN source: a WITH
+// relation, or the rows the scanner of a registry resource produces, such
+// as listed objects, pod logs or RBAC permissions. Log rows may already be
+// filtered by WHERE. scope carries the namespace and selectors; refs are the
+// field references of the query, used to decide which derived fields to
+// compute.
+func (e *Executor) scanRows(ctx context.Context, resDef *registry.ResourceDefinition, rel *relation, scope *parser.Query, refs []string) ([]map[string]interface{}, error) {
+	if rel != nil {
+		return rel.scan(), nil
+	}
+	return scannerFor(resDef).scan(e, ctx, resDef, scope, refs)
+}
 
-	// Resolve subqueries in WHERE conditions (execute once, cache results)
-	if query.Conditions != nil {
-		if err := e.resolveSubQueries(query.Conditions, query); err != nil {
-			return nil, nil, err
+// scanObjects produces one row per listed object of resDef, or per
+// descendant for DESCENDANTS OF. prepare, if not nil, may change or replace
+// the objects before rows are built.
+func (e *Executor) scanObjects(ctx context.Context, resDef *registry.ResourceDefinition, scope *parser.Query, refs []string, prepare func(e *Executor, ctx context.Context, items []unstructured.Unstructured) ([]unstructured.Unstructured, error)) ([]map[string]interface{}, error) {
+	// Fetch resources from K8s
+	var items []unstructured.Unstructured
+	var depths []int
+	var err error
+	if scope.DescendantsOf != nil {
+		items, depths, err = e.fetchDescendants(ctx, scope.DescendantsOf, scope.Namespace)
+	} else if resDef.GroupVersionResource.Resource == "" {
+		err = fmt.Errorf("resource %s must be queried as DESCENDANTS OF resource/name", resDef.Name)
+	} else {
+		items, err = e.fetchResources(ctx, resDef, scope)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Join live usage from metrics.k8s.io (cpu.usage, mem.usage, ...)
+	if needsMetrics(resDef, refs) {
+		if err := e.attachMetrics(ctx, resDef, items, scope.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	if prepare != nil {
+		if items, err = prepare(e, ctx, items); err != nil {
+			return nil, err
 		}
 	}
 
-	// Extract field values and apply WHERE filter
-	var results []map[string]interface{}
-	for _, item := range items {
-		row := e.extractRow(&item, resDef, fields)
+	// Collect dynamic map sub-fields (e.g. "labels.app") from query
+	dynamicMapFields := collectDynamicMapFields(refs, resDef)
+	rawPaths := collectRawPaths(refs, resDef)
 
-		// Extract dynamic map sub-fields (e.g. labels.app from the labels map)
-		if len(dynamicMapFields) > 0 {
-			extractDynamicMapFields(row, dynamicMapFields, resDef)
+	rows := make([]map[string]interface{}, 0, len(items))
+	for i := range items {
+		rows = append(rows, e.buildRow(&items[i], resDef, dynamicMapFields, rawPaths))
+	}
+	for i, depth := range depths {
+		rows[i]["depth"] = depth
+	}
+
+	// Resolve virtual owner fields (owner.kind, root_owner, ...)
+	if err := e.addOwnerFields(ctx, rows, items, refs, scope.Namespace); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// buildRow extracts every registry field of item, plus the dynamic map
+// sub-fields and raw paths referenced by the query, into a row keyed by field name.
+func (e *Executor) buildRow(item *unstructured.Unstructured, resDef *registry.ResourceDefinition, dynamicMapFields, rawPaths []string) map[string]interface{} {
+	row := e.extractRow(item, resDef, nil)
+
+	// Extract dynamic map sub-fields (e.g. labels.app from the labels map)
+	if len(dynamicMapFields) > 0 {
+		extractDynamicMapFields(row, dynamicMapFields, resDef)
+	}
+
+	// Extract raw object paths (e.g. spec.strategy.type, annotations['x/y'])
+	for _, path := range rawPaths {
+		segments, _ := resDef.ResolveRawPath(path)
+		row[path] = extractFromValue(item.Object, segments)
+	}
+
+	// Always include namespace for filtering even if not in selected fields
+	if _, has := row["namespace"]; !has {
+		if nsDef, ok := resDef.Fields["namespace"]; ok {
+			row["namespace"] = e.extractField(item, nsDef.JSONPath)
 		}
+	}
 
-		// Always include namespace for filtering even if not in selected fields
-		if _, has := row["namespace"]; !has {
-			if nsDef, ok := resDef.Fields["namespace"]; ok {
-				row["namespace"] = e.extractField(&item, nsDef.JSONPath)
-			}
+	return row
+}
+
+// applyClauses runs the clauses shared by single-resource and JOIN queries:
+// subqueries, WHERE, aggregation with HAVING, DISTINCT, ORDER BY and LIMIT/OFFSET.
+func (e *Executor) applyClauses(ctx context.Context, query *parser.Query, rows []map[string]interface{}, fields []string) ([]map[string]interface{}, []string, error) {
+	// Resolve subqueries in WHERE conditions (execute once, cache results)
+	if query.Conditions != nil {
+		if err := e.resolveSubQueries(ctx, query.Conditions, query); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		// Apply WHERE conditions
+	// Apply WHERE conditions
+	var step *planNode
+	if query.Conditions != nil && len(query.Conditions.Conditions)+len(query.Conditions.SubGroups) > 0 {
+		step = addStep(ctx, "Filter", "WHERE", query.Conditions.String())
+	}
+	var results []map[string]interface{}
+	for _, row := range rows {
 		if query.Conditions != nil && !query.Conditions.Evaluate(row) {
 			continue
 		}
-
 		results = append(results, row)
 	}
+	step.done(len(results))
 
 	// Apply aggregations if present
 	if len(query.Aggregates) > 0 || len(query.GroupBy) > 0 {
+		target := "all rows"
+		if len(query.GroupBy) > 0 {
+			target = "GROUP BY " + strings.Join(query.GroupBy, ", ")
+		}
+		step := addStep(ctx, "Aggregate", target, describeAggregation(query))
 		results, fields = applyAggregation(results, query, fields)
+		step.done(len(results))
 	}
 
 	// Apply DISTINCT
 	if query.Distinct {
+		step := addStep(ctx, "Distinct", "DISTINCT", strings.Join(fields, ", "))
 		results = applyDistinct(results, fields)
+		step.done(len(results))
 	}
 
 	// Apply ORDER BY
 	if len(query.OrderBy) > 0 {
+		step := addStep(ctx, "Sort", "ORDER BY", describeOrderBy(query.OrderBy))
 		sortResults(results, query.OrderBy)
+		step.done(len(results))
 	}
 
 	// Apply LIMIT and OFFSET
-	results = applyLimitOffset(results, query.Limit, query.Offset)
+	if query.Limit > 0 || query.Offset > 0 {
+		target := fmt.Sprintf("LIMIT %d", query.Limit)
+		if query.Offset > 0 {
+			target += fmt.Sprintf(" OFFSET %d", query.Offset)
+		}
+		step := addStep(ctx, "Limit", target, "")
+		results = applyLimitOffset(results, query.Limit, query.Offset)
+		step.done(len(results))
+	}
 
+	addStep(ctx, "Project", "fields", strings.Join(fields, ", ")).done(len(results))
 	return results, fields, nil
 }
 
-func (e *Executor) fetchResources(resDef *registry.ResourceDefinition, query *parser.Query) ([]unstructured.Unstructured, error) {
+func (e *Executor) fetchResources(ctx context.Context, resDef *registry.ResourceDefinition, query *parser.Query) ([]unstructured.Unstructured, error) {
 	listOptions := metav1.ListOptions{}
 	if query.FieldSelector != "" {
 		listOptions.FieldSelector = query.FieldSelector
@@ -154,21 +369,17 @@ func (e *Executor) fetchResources(resDef *registry.ResourceDefinition, query *pa
 				labels = append(labels, fmt.Sprintf("%s=%s", k, v))
 			}
 		}
+		// Sorted, so the same selector always makes the same cache key
+		sort.Strings(labels)
 		listOptions.LabelSelector = strings.Join(labels, ",")
 	}
 
-	gvr := resDef.GroupVersionResource
-	var list *unstructured.UnstructuredList
-	var err error
-
+	namespace := query.Namespace
 	if !resDef.Namespaced {
 		// Cluster-scoped resource (e.g. node)
-		list, err = e.dynamicClient.Resource(gvr).List(context.TODO(), listOptions)
-	} else if query.Namespace == "*" || query.Namespace == "" {
-		list, err = e.dynamicClient.Resource(gvr).Namespace("").List(context.TODO(), listOptions)
-	} else {
-		list, err = e.dynamicClient.Resource(gvr).Namespace(query.Namespace).List(context.TODO(), listOptions)
+		namespace = ""
 	}
+	list, err := e.list(ctx, resDef.GroupVersionResource, namespace, listOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list %s: %w", resDef.Name, err)
 	}
@@ -176,6 +387,34 @@ func (e *Executor) fetchResources(resDef *registry.ResourceDefinition, query *pa
 	return list.Items, nil
 }
 
+// list lists gvr from the source in namespace, or in all namespaces if it
+// is "" or "*".
+func (e *Executor) list(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if isDryRun(ctx) {
+		return &unstructured.UnstructuredList{}, nil
+	}
+	if namespace == "*" {
+		namespace = ""
+	}
+	return e.source.List(ctx, gvr, namespace, opts)
+}
+
+// fetchAll runs fetch for 0..n-1 concurrently, at most maxConcurrentFetches
+// at a time. Each call gets its own copy of e, so WITH relations bound by one
+// are not seen by the others. The first error cancels the context of the
+// rest and is returned.
+func (e *Executor) fetchAll(ctx context.Context, n int, fetch func(ctx context.Context, e *Executor, i int) error) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentFetches)
+	for i := 0; i < n; i++ {
+		fork := *e
+		g.Go(func() error {
+			return fetch(ctx, &fork, i)
+		})
+	}
+	return g.Wait()
+}
+
 func (e *Executor) resolveFields(query *parser.Query, resDef *registry.ResourceDefinition) []string {
 	// * or empty → use DefaultFields, fallback to all fields
 	if len(query.Fields) == 0 || (len(query.Fields) == 1 && query.Fields[0] == "*") {
@@ -186,12 +425,8 @@ func (e *Executor) resolveFields(query *parser.Query, resDef *registry.ResourceD
 	// the shell likely expanded * to filenames. Fall back to defaults.
 	hasValidField := false
 	for _, f := range query.Fields {
-		if _, ok := resDef.Fields[f]; ok {
-			hasValidField = true
-			break
-		}
-		// Accept dot-notation map sub-fields (e.g. "labels.app")
-		if _, _, ok := resDef.IsMapSubField(f); ok {
+		// Accept dot-notation map sub-fields (e.g. "labels.app") and owner fields
+		if resDef.HasField(f) {
 			hasValidField = true
 			break
 		}
@@ -219,26 +454,11 @@ func (e *Executor) extractRow(item *unstructured.Unstructured, resDef *registry.
 	row := make(map[string]interface{})
 	// Extract all known fields so WHERE conditions can reference any field
 	for fieldName, fieldDef := range resDef.Fields {
-		value := e.extractField(item, fieldDef.JSONPath)
-
-		// Parse quantity strings for normalized fields
-		if strings.HasSuffix(fieldName, "-m") {
-			// CPU field in millicores
-			if strVal, ok := value.(string); ok {
-				if parsed, err := parser.ParseCPUToMillicores(strVal); err == nil {
-					value = parsed
-				}
-			}
-		} else if strings.HasSuffix(fieldName, "-mi") {
-			// Memory field in MiB
-			if strVal, ok := value.(string); ok {
-				if parsed, err := parser.ParseMemoryToMiB(strVal); err == nil {
-					value = parsed
-				}
-			}
+		if fieldDef.Expr != "" {
+			row[fieldName] = evalExpr(item, fieldDef.Expr)
+			continue
 		}
-
-		row[fieldName] = value
+		row[fieldName] = e.extractField(item, fieldDef.JSONPath)
 	}
 	return row
 }
@@ -252,9 +472,23 @@ func (e *Executor) extractField(obj *unstructured.Unstructured, jsonPath string)
 }
 
 // extractFromValue navigates a nested structure following the given path parts.
-// Supports [*] to iterate over arrays and continue extracting sub-paths.
+// Supports [*] to iterate over arrays and continue extracting sub-paths, and
+// [n] to pick one array element.
 func extractFromValue(current interface{}, parts []string) interface{} {
 	for i, part := range parts {
+		if name, index, ok := arrayIndex(part); ok {
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			slice, ok := m[name].([]interface{})
+			if !ok || index >= len(slice) {
+				return nil
+			}
+			current = slice[index]
+			continue
+		}
+
 		if strings.Contains(part, "[*]") {
 			part = strings.TrimSuffix(part, "[*]")
 			m, ok := current.(map[string]interface{})
@@ -354,80 +588,139 @@ func applyLimitOffset(results []map[string]interface{}, limit, offset int) []map
 }
 
 // resolveQueryAliases resolves field aliases (e.g. "ns" → "namespace") throughout the query.
-func resolveQueryAliases(query *parser.Query, resDef *registry.ResourceDefinition) {
+// resolve maps a single field reference to its canonical name.
+func resolveQueryAliases(query *parser.Query, resolve func(string) string) {
 	// Resolve aliases in selected fields
 	for i, f := range query.Fields {
-		query.Fields[i] = resDef.ResolveFieldAlias(f)
+		query.Fields[i] = resolve(f)
+	}
+
+	// Resolve aliases in aggregate arguments
+	for i, agg := range query.Aggregates {
+		if agg.Field != "*" {
+			query.Aggregates[i].Field = resolve(agg.Field)
+		}
 	}
 
 	// Resolve aliases in WHERE conditions
 	if query.Conditions != nil {
-		resolveConditionAliases(query.Conditions, resDef)
+		resolveConditionAliases(query.Conditions, resolve)
 	}
 
 	// Resolve aliases in ORDER BY
 	for i, ob := range query.OrderBy {
-		query.OrderBy[i].Field = resDef.ResolveFieldAlias(ob.Field)
+		query.OrderBy[i].Field = resolve(ob.Field)
 	}
 
 	// Resolve aliases in GROUP BY
 	for i, gb := range query.GroupBy {
-		query.GroupBy[i] = resDef.ResolveFieldAlias(gb)
+		query.GroupBy[i] = resolve(gb)
 	}
 
 	// Resolve aliases in HAVING
 	if query.Having != nil {
-		resolveConditionAliases(query.Having, resDef)
+		resolveConditionAliases(query.Having, resolve)
+	}
+
+	// Resolve aliases in JOIN ON conditions
+	for i := range query.Joins {
+		join := &query.Joins[i]
+		for j, cond := range join.Conditions {
+			join.Conditions[j].LeftField = resolve(cond.LeftField)
+			join.Conditions[j].RightField = resolve(cond.RightField)
+		}
+		if join.LeftField != "" {
+			join.LeftField = resolve(join.LeftField)
+			join.RightField = resolve(join.RightField)
+		}
 	}
 }
 
-func resolveConditionAliases(group *parser.ConditionGroup, resDef *registry.ResourceDefinition) {
+func resolveConditionAliases(group *parser.ConditionGroup, resolve func(string) string) {
 	for i, cond := range group.Conditions {
-		group.Conditions[i].Field = resDef.ResolveFieldAlias(cond.Field)
+		group.Conditions[i].Field = resolve(cond.Field)
+	}
+	for _, sub := range group.SubGroups {
+		resolveConditionAliases(sub, resolve)
+	}
+}
+
+// queryFieldRefs returns every field name the query references outside of
+// subqueries: selected fields, aggregate arguments, WHERE, JOIN ON, GROUP BY,
+// HAVING and ORDER BY.
+func queryFieldRefs(query *parser.Query) []string {
+	var refs []string
+	refs = append(refs, query.Fields...)
+	for _, agg := range query.Aggregates {
+		refs = append(refs, agg.Field)
+	}
+	if query.Conditions != nil {
+		refs = appendConditionFieldRefs(refs, query.Conditions)
+	}
+	for _, join := range query.Joins {
+		for _, cond := range join.Conditions {
+			refs = append(refs, cond.LeftField, cond.RightField)
+		}
+	}
+	refs = append(refs, query.GroupBy...)
+	if query.Having != nil {
+		refs = appendConditionFieldRefs(refs, query.Having)
+	}
+	for _, ob := range query.OrderBy {
+		refs = append(refs, ob.Field)
+	}
+	return refs
+}
+
+func appendConditionFieldRefs(refs []string, group *parser.ConditionGroup) []string {
+	for _, cond := range group.Conditions {
+		refs = append(refs, cond.Field)
 	}
 	for _, sub := range group.SubGroups {
-		resolveConditionAliases(sub, resDef)
+		refs = appendConditionFieldRefs(refs, sub)
 	}
+	return refs
 }
 
-// collectDynamicMapFields scans the query for dot-notation fields that reference
-// map-type fields (e.g. "labels.app"). Returns a deduplicated list.
-func collectDynamicMapFields(query *parser.Query, resDef *registry.ResourceDefinition) []string {
+// collectDynamicMapFields filters field references down to dot-notation fields
+// that reference map-type fields (e.g. "labels.app"). Returns a deduplicated list.
+func collectDynamicMapFields(refs []string, resDef *registry.ResourceDefinition) []string {
 	seen := make(map[string]bool)
 	var result []string
-
-	add := func(field string) {
+	for _, field := range refs {
 		if _, _, ok := resDef.IsMapSubField(field); ok && !seen[field] {
 			seen[field] = true
 			result = append(result, field)
 		}
 	}
+	return result
+}
 
-	for _, f := range query.Fields {
-		add(f)
+// arrayIndex splits a path part like "containers[0]" into name and index.
+func arrayIndex(part string) (string, int, bool) {
+	open := strings.IndexByte(part, '[')
+	if open <= 0 || !strings.HasSuffix(part, "]") {
+		return "", 0, false
 	}
-	if query.Conditions != nil {
-		collectDynamicMapFieldsFromConditions(query.Conditions, resDef, &result, seen)
+	index, err := strconv.Atoi(part[open+1 : len(part)-1])
+	if err != nil || index < 0 {
+		return "", 0, false
 	}
-	for _, ob := range query.OrderBy {
-		add(ob.Field)
-	}
-	for _, gb := range query.GroupBy {
-		add(gb)
-	}
-	return result
+	return part[:open], index, true
 }
 
-func collectDynamicMapFieldsFromConditions(group *parser.ConditionGroup, resDef *registry.ResourceDefinition, result *[]string, seen map[string]bool) {
-	for _, cond := range group.Conditions {
-		if _, _, ok := resDef.IsMapSubField(cond.Field); ok && !seen[cond.Field] {
-			seen[cond.Field] = true
-			*result = append(*result, cond.Field)
+// collectRawPaths filters field references down to raw object paths that are
+// not declared fields (e.g. ".spec.replicas", "annotations['x/y']").
+func collectRawPaths(refs []string, resDef *registry.ResourceDefinition) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, field := range refs {
+		if resDef.IsRawPath(field) && !seen[field] {
+			seen[field] = true
+			result = append(result, field)
 		}
 	}
-	for _, sub := range group.SubGroups {
-		collectDynamicMapFieldsFromConditions(sub, resDef, result, seen)
-	}
+	return result
 }
 
 // extractDynamicMapFields populates the row with flattened map sub-field values.
@@ -467,23 +760,27 @@ func applyDistinct(results []map[string]interface{}, fields []string) []map[stri
 	return unique
 }
 
-// resolveSubQueries walks all conditions and executes any subqueries,
-// storing the results in Condition.SubQueryValues for later evaluation.
-// Called once before the filter loop so subqueries are not re-executed per row.
-func (e *Executor) resolveSubQueries(group *parser.ConditionGroup, outerQuery *parser.Query) error {
-	for i := range group.Conditions {
-		cond := &group.Conditions[i]
-		if cond.SubQuery == nil {
-			continue
-		}
-
+// resolveSubQueries executes every subquery in the conditions, storing the
+// results in Condition.SubQueryValues for later evaluation. Called once before
+// the filter loop so subqueries are not re-executed per row; independent
+// subqueries run concurrently.
+func (e *Executor) resolveSubQueries(ctx context.Context, group *parser.ConditionGroup, outerQuery *parser.Query) error {
+	conds := subQueryConditions(nil, group)
+	steps := make([]*planNode, len(conds))
+	for i, cond := range conds {
 		// Inherit namespace from outer query if not specified
 		if cond.SubQuery.Namespace == "" {
 			cond.SubQuery.Namespace = outerQuery.Namespace
 		}
+		detail := "runs once, before filtering"
+		if len(conds) > 1 {
+			detail += ", in parallel"
+		}
+		steps[i] = addStep(ctx, "Subquery", fmt.Sprintf("%s %s", cond.Field, cond.Operator), detail)
+	}
 
-		// Execute subquery
-		results, fields, err := e.Execute(cond.SubQuery)
+	return e.fetchAll(ctx, len(conds), func(ctx context.Context, e *Executor, i int) error {
+		results, fields, err := e.execute(steps[i].enter(ctx), conds[i].SubQuery)
 		if err != nil {
 			return fmt.Errorf("subquery error: %w", err)
 		}
@@ -501,16 +798,24 @@ func (e *Executor) resolveSubQueries(group *parser.ConditionGroup, outerQuery *p
 				}
 			}
 		}
-		cond.SubQueryValues = values
-	}
+		conds[i].SubQueryValues = values
+		steps[i].done(len(values))
+		return nil
+	})
+}
 
-	for _, sub := range group.SubGroups {
-		if err := e.resolveSubQueries(sub, outerQuery); err != nil {
-			return err
+// subQueryConditions appends the conditions of group and its subgroups that
+// hold a subquery to conds.
+func subQueryConditions(conds []*parser.Condition, group *parser.ConditionGroup) []*parser.Condition {
+	for i := range group.Conditions {
+		if group.Conditions[i].SubQuery != nil {
+			conds = append(conds, &group.Conditions[i])
 		}
 	}
-
-	return nil
+	for _, sub := range group.SubGroups {
+		conds = subQueryConditions(conds, sub)
+	}
+	return conds
 }
 
 func FormatAge(timestamp interface{}) string {
@@ -0,0 +1,451 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+	"github.com/bangmodtechnology/kselect/pkg/source"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	podGVR        = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	serviceGVR    = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	replicaSetGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+	jobGVR        = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	cronJobGVR    = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}
+)
+
+// newFakeExecutor builds an Executor serving objects from memory.
+func newFakeExecutor(objects ...runtime.Object) *Executor {
+	e := NewWithRegistry(fakeSource(objects...), newFakeRegistry())
+	e.CurrentNamespace = "default"
+	return e
+}
+
+// fakeSource serves objects, which must be *unstructured.Unstructured.
+func fakeSource(objects ...runtime.Object) *source.Memory {
+	items := make([]unstructured.Unstructured, len(objects))
+	for i, obj := range objects {
+		items[i] = *obj.(*unstructured.Unstructured)
+	}
+	return source.NewMemory(items)
+}
+
+func newFakeRegistry() *registry.Registry {
+	reg := registry.NewRegistry()
+	reg.Register(&registry.ResourceDefinition{
+		Name:                 "pod",
+		Aliases:              []string{"pods", "po"},
+		GroupVersionResource: podGVR,
+		Namespaced:           true,
+		DefaultFields:        []string{"name", "status"},
+		Fields: map[string]registry.FieldDefinition{
+			"name":       {Name: "name", JSONPath: "{.metadata.name}", Type: "string"},
+			"namespace":  {Name: "namespace", Aliases: []string{"ns"}, JSONPath: "{.metadata.namespace}", Type: "string"},
+			"status":     {Name: "status", JSONPath: "{.status.phase}", Type: "string"},
+			"restarts":   {Name: "restarts", JSONPath: "{.status.containerStatuses[*].restartCount}", Type: "int"},
+			"mem.req-mi": {Name: "mem.req-mi", Expr: "sum(memory(.spec.containers[*].resources.requests.memory))", Type: "int"},
+			"labels":     {Name: "labels", Aliases: []string{"lbl"}, JSONPath: "{.metadata.labels}", Type: "map"},
+		},
+	})
+	reg.Register(&registry.ResourceDefinition{
+		Name:                 "service",
+		Aliases:              []string{"services", "svc"},
+		GroupVersionResource: serviceGVR,
+		Namespaced:           true,
+		DefaultFields:        []string{"name", "type"},
+		Fields: map[string]registry.FieldDefinition{
+			"name":      {Name: "name", JSONPath: "{.metadata.name}", Type: "string"},
+			"namespace": {Name: "namespace", Aliases: []string{"ns"}, JSONPath: "{.metadata.namespace}", Type: "string"},
+			"type":      {Name: "type", JSONPath: "{.spec.type}", Type: "string"},
+			"selector":  {Name: "selector", JSONPath: "{.spec.selector}", Type: "map"},
+		},
+	})
+	for name, gvr := range map[string]schema.GroupVersionResource{
+		"deployment": deploymentGVR,
+		"replicaset": replicaSetGVR,
+		"job":        jobGVR,
+		"cronjob":    cronJobGVR,
+	} {
+		reg.Register(&registry.ResourceDefinition{
+			Name:                 name,
+			GroupVersionResource: gvr,
+			Namespaced:           true,
+			DefaultFields:        []string{"name"},
+			Fields: map[string]registry.FieldDefinition{
+				"name":      {Name: "name", JSONPath: "{.metadata.name}", Type: "string"},
+				"namespace": {Name: "namespace", Aliases: []string{"ns"}, JSONPath: "{.metadata.namespace}", Type: "string"},
+			},
+		})
+	}
+	reg.Register(&registry.ResourceDefinition{
+		Name:          "descendants",
+		Namespaced:    true,
+		DefaultFields: []string{"kind", "name", "depth"},
+		Fields: map[string]registry.FieldDefinition{
+			"kind":      {Name: "kind", JSONPath: "{.kind}", Type: "string"},
+			"name":      {Name: "name", JSONPath: "{.metadata.name}", Type: "string"},
+			"namespace": {Name: "namespace", JSONPath: "{.metadata.namespace}", Type: "string"},
+			"depth":     {Name: "depth", Type: "int"},
+		},
+	})
+	return reg
+}
+
+func newPod(namespace, name, app, phase string, restarts int64, memory string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+			"labels":    map[string]interface{}{"app": app},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name": "main",
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"memory": memory},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"phase": phase,
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"restartCount": restarts},
+			},
+		},
+	}}
+}
+
+func newService(namespace, name, app string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"type":     "ClusterIP",
+			"selector": map[string]interface{}{"app": app},
+		},
+	}}
+}
+
+func joinFixtures() []runtime.Object {
+	return []runtime.Object{
+		newPod("default", "web-1", "web", "Running", 3, "128Mi"),
+		newPod("default", "web-2", "web", "Running", 1, "1Gi"),
+		newPod("default", "api-1", "api", "Pending", 0, "256Mi"),
+		newPod("other", "web-9", "web", "Running", 7, "64Mi"),
+		newService("default", "web-svc", "web"),
+		newService("default", "api-svc", "api"),
+		newService("other", "web-svc", "web"),
+	}
+}
+
+func mustParse(t *testing.T, sql string) *parser.Query {
+	t.Helper()
+	query, err := parser.Parse(sql)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", sql, err)
+	}
+	return query
+}
+
+func TestExecuteJoinGroupByWithAggregates(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+	query := mustParse(t, "svc.name, COUNT as pods, SUM.p.restarts as restarts FROM pod p INNER JOIN service svc ON p.labels.app = svc.selector.app WHERE p.namespace = default GROUP BY svc.name HAVING pods > 1")
+
+	results, fields, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 group after HAVING, got %d: %v", len(results), results)
+	}
+	if results[0]["svc.name"] != "web-svc" {
+		t.Errorf("Expected group web-svc, got %v", results[0]["svc.name"])
+	}
+	if results[0]["pods"] != 2 {
+		t.Errorf("Expected 2 pods, got %v", results[0]["pods"])
+	}
+	if results[0]["restarts"] != 4.0 {
+		t.Errorf("Expected 4 restarts, got %v", results[0]["restarts"])
+	}
+	expectedFields := []string{"svc.name", "pods", "restarts"}
+	if len(fields) != len(expectedFields) {
+		t.Fatalf("Expected fields %v, got %v", expectedFields, fields)
+	}
+	for i, f := range expectedFields {
+		if fields[i] != f {
+			t.Errorf("Expected field %d to be %q, got %q", i, f, fields[i])
+		}
+	}
+}
+
+func TestExecuteJoinResolvesAliasesAndQuantities(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+	query := mustParse(t, "p.name, p.mem.req-mi, svc.name FROM pod p INNER JOIN svc ON p.lbl.app = svc.selector.app WHERE p.ns = default AND p.mem.req-mi GT 200 ORDER BY p.mem.req-mi DESC")
+
+	results, _, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 rows, got %d: %v", len(results), results)
+	}
+	if results[0]["p.name"] != "web-2" || results[0]["p.mem.req-mi"] != int64(1024) {
+		t.Errorf("Expected web-2 with 1024 MiB first, got %v / %v", results[0]["p.name"], results[0]["p.mem.req-mi"])
+	}
+	if results[1]["p.name"] != "api-1" || results[1]["svc.name"] != "api-svc" {
+		t.Errorf("Expected api-1 joined to api-svc second, got %v / %v", results[1]["p.name"], results[1]["svc.name"])
+	}
+}
+
+func TestExecuteJoinSideNamespace(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+	query := mustParse(t, "p.name, p.namespace, svc.namespace FROM pod p INNER JOIN service svc ON p.labels.app = svc.selector.app WHERE p.namespace = other AND svc.namespace = default")
+
+	if query.Namespace != "other" {
+		t.Fatalf("Expected primary namespace 'other', got %q", query.Namespace)
+	}
+	if query.Joins[0].Namespace != "default" {
+		t.Fatalf("Expected join namespace 'default', got %q", query.Joins[0].Namespace)
+	}
+
+	results, _, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 row, got %d: %v", len(results), results)
+	}
+	if results[0]["p.name"] != "web-9" || results[0]["svc.namespace"] != "default" {
+		t.Errorf("Expected web-9 joined to default/web-svc, got %v", results[0])
+	}
+}
+
+func TestExecuteJoinDistinct(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+	query := mustParse(t, "DISTINCT svc.name FROM pod p INNER JOIN service svc ON p.labels.app = svc.selector.app WHERE p.namespace = default AND svc.namespace = default")
+
+	results, _, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 distinct services, got %d: %v", len(results), results)
+	}
+}
+
+func TestExecuteJoinSubquery(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+	query := mustParse(t, "p.name FROM pod p INNER JOIN service svc ON p.labels.app = svc.selector.app WHERE p.namespace = default AND p.name IN (SELECT name FROM pod WHERE status = Pending)")
+
+	results, _, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 1 || results[0]["p.name"] != "api-1" {
+		t.Errorf("Expected only api-1, got %v", results)
+	}
+}
+
+func TestExecuteCache(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+	e.EnableCache(time.Minute)
+	query := mustParse(t, "name FROM pod WHERE namespace = default AND name IN (SELECT name FROM pod WHERE namespace = default)")
+
+	if _, _, err := e.Execute(query); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := e.CacheStats(); got != (source.CacheStats{Hits: 1, Misses: 1}) {
+		t.Errorf("Expected the subquery list to be reused, got %d hits and %d misses", got.Hits, got.Misses)
+	}
+	if _, _, err := e.Execute(query); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := e.CacheStats(); got.Misses != 1 {
+		t.Errorf("Expected the repeated query to be cached, got %d hits and %d misses", got.Hits, got.Misses)
+	}
+
+	e.Refresh()
+	if _, _, err := e.Execute(query); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := e.CacheStats(); got.Misses != 2 {
+		t.Errorf("Expected Refresh to drop cached lists, got %d hits and %d misses", got.Hits, got.Misses)
+	}
+}
+
+// gatedSource holds lists of the gated resources until want of them are in
+// flight at once, failing them if ctx ends first. Lists of fail return an error.
+type gatedSource struct {
+	*source.Memory
+	gated   map[string]bool
+	want    int
+	fail    string
+	mu      sync.Mutex
+	arrived int
+	release chan struct{}
+	aborted []error
+}
+
+func newGatedSource(want int, gated ...string) *gatedSource {
+	s := &gatedSource{Memory: fakeSource(joinFixtures()...), gated: map[string]bool{}, want: want, release: make(chan struct{})}
+	for _, res := range gated {
+		s.gated[res] = true
+	}
+	return s
+}
+
+func (s *gatedSource) List(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if gvr.Resource == s.fail {
+		return nil, fmt.Errorf("%s unavailable", gvr.Resource)
+	}
+	if s.gated[gvr.Resource] {
+		s.mu.Lock()
+		s.arrived++
+		if s.arrived == s.want {
+			close(s.release)
+		}
+		s.mu.Unlock()
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.aborted = append(s.aborted, ctx.Err())
+			s.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+	return s.Memory.List(ctx, gvr, namespace, opts)
+}
+
+func TestExecuteFetchesConcurrently(t *testing.T) {
+	tests := []struct {
+		name  string
+		sql   string
+		gated []string
+	}{
+		{"join sides", "p.name FROM pod p INNER JOIN service svc ON p.labels.app = svc.selector.app WHERE p.namespace = default", []string{"pods", "services"}},
+		{"subqueries", "name FROM pod WHERE namespace = default AND name IN (SELECT name FROM service) AND name NOT IN (SELECT name FROM service WHERE namespace = default)", []string{"services"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newFakeExecutor()
+			e.source = newGatedSource(2, tt.gated...)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if _, _, err := e.ExecuteContext(ctx, mustParse(t, tt.sql)); err != nil {
+				t.Fatalf("Expected the lists to run at the same time, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExecuteJoinCancelsOnError(t *testing.T) {
+	src := newGatedSource(2, "pods")
+	src.fail = "services"
+	e := newFakeExecutor()
+	e.source = src
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _, err := e.ExecuteContext(ctx, mustParse(t, "p.name FROM pod p INNER JOIN service svc ON p.labels.app = svc.selector.app"))
+	if err == nil || !strings.Contains(err.Error(), "services unavailable") {
+		t.Fatalf("Expected the service list error, got %v", err)
+	}
+	if len(src.aborted) != 1 || !errors.Is(src.aborted[0], context.Canceled) {
+		t.Errorf("Expected the pod list to be cancelled, got %v", src.aborted)
+	}
+}
+
+func TestExecuteTimeout(t *testing.T) {
+	e := newFakeExecutor()
+	// Pod lists wait for a second one that never comes
+	e.source = newGatedSource(2, "pods")
+	e.Timeout = 50 * time.Millisecond
+
+	_, _, err := e.Execute(mustParse(t, "name FROM pod WHERE name IN (SELECT name FROM service)"))
+	if err == nil || err.Error() != "query timed out after 50ms: context deadline exceeded" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the query to time out, got %v", err)
+	}
+
+	// Cancelling the caller's context is not reported as a timeout
+	e.source = newGatedSource(2, "pods")
+	e.Timeout = time.Minute
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, _, err = e.ExecuteContext(ctx, mustParse(t, "name FROM pod"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the query to be cancelled, got %v", err)
+	}
+}
+
+func TestExecuteUnboundParam(t *testing.T) {
+	e := newFakeExecutor(newPod("prod", "web-1", "web", "Running", 0, "128Mi"))
+	for _, input := range []string{
+		"name FROM pod WHERE namespace = :ns",
+		"name FROM pod WHERE name IN (web-1, $1)",
+		"name FROM pod WHERE name IN (SELECT name FROM pod WHERE namespace = :ns)",
+	} {
+		_, _, err := e.Execute(mustParse(t, input))
+		if err == nil || !strings.Contains(err.Error(), "missing value for parameter") {
+			t.Errorf("Execute(%q) error = %v, want missing value", input, err)
+		}
+	}
+}
+
+func TestExecuteRawPaths(t *testing.T) {
+	web := newPod("default", "web-1", "web", "Running", 0, "128Mi")
+	web.SetAnnotations(map[string]string{"kubectl.kubernetes.io/restartedAt": "2024-01-01"})
+	api := newPod("default", "api-1", "api", "Running", 0, "128Mi")
+	e := newFakeExecutor(web, api)
+
+	query := mustParse(t, "name, .spec.containers[0].name, annotations['kubectl.kubernetes.io/restartedAt'] FROM pod WHERE namespace = default AND metadata.labels['app'] = web")
+	results, fields, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(fields) != 3 || fields[2] != "annotations['kubectl.kubernetes.io/restartedAt']" {
+		t.Errorf("Expected raw paths as column names, got %v", fields)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 row, got %d: %v", len(results), results)
+	}
+	row := results[0]
+	if row[".spec.containers[0].name"] != "main" || row["annotations['kubectl.kubernetes.io/restartedAt']"] != "2024-01-01" {
+		t.Errorf("Unexpected raw path values: %v", row)
+	}
+
+	// Raw paths sort and group like declared fields
+	query = mustParse(t, "status.phase, COUNT as pods FROM pod GROUP BY status.phase")
+	results, _, err = e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 1 || results[0]["pods"] != 2 {
+		t.Errorf("Expected one group of 2 pods, got %v", results)
+	}
+}
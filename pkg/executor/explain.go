@@ -0,0 +1,204 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+)
+
+// planNode is one step of a query plan: a fetch, a join, a filter, ... EXPLAIN
+// fills in what the step does; EXPLAIN ANALYZE also the rows it produced and
+// the time it took, including the time of its children.
+type planNode struct {
+	step     string
+	target   string // what the step works on: a resource, a clause
+	detail   string
+	dry      bool // EXPLAIN without ANALYZE: nothing is listed
+	start    time.Time
+	rows     int
+	elapsed  time.Duration
+	children []*planNode
+}
+
+// planKey is the context key of the plan node the stages of a query add
+// their steps to.
+type planKey struct{}
+
+// addStep adds a step to the plan of the query running in ctx, or returns
+// nil if the query is not being explained. planNode methods accept nil, so
+// callers need not check. Steps must be added by one goroutine at a time, so
+// steps that run concurrently are added before they start.
+func addStep(ctx context.Context, step, target, detail string) *planNode {
+	parent, _ := ctx.Value(planKey{}).(*planNode)
+	if parent == nil {
+		return nil
+	}
+	n := &planNode{step: step, target: target, detail: detail, dry: parent.dry, start: time.Now()}
+	parent.children = append(parent.children, n)
+	return n
+}
+
+// enter returns ctx with n as the node later steps are added under.
+func (n *planNode) enter(ctx context.Context) context.Context {
+	if n == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, planKey{}, n)
+}
+
+// done records that the step produced rows rows.
+func (n *planNode) done(rows int) {
+	if n == nil {
+		return
+	}
+	n.rows = rows
+	n.elapsed = time.Since(n.start)
+}
+
+// isDryRun reports whether ctx belongs to a plain EXPLAIN, whose lists
+// return nothing so the plan is built without reading the cluster.
+func isDryRun(ctx context.Context) bool {
+	n, _ := ctx.Value(planKey{}).(*planNode)
+	return n != nil && n.dry
+}
+
+// explain runs query to record its plan and returns the plan as rows, one
+// per step, with nested steps indented. Long details are cut short in table
+// output; -o wide shows them in full. Without ANALYZE nothing is listed;
+// with it the query runs in full and each step gets its row count and time.
+func (e *Executor) explain(ctx context.Context, query *parser.Query) ([]map[string]interface{}, []string, error) {
+	root := &planNode{dry: !query.Analyze, start: time.Now()}
+	results, _, err := e.execute(root.enter(ctx), query)
+	if err != nil {
+		return nil, nil, err
+	}
+	root.done(len(results))
+
+	fields := []string{"step", "target", "detail"}
+	if query.Analyze {
+		fields = append(fields, "rows", "time")
+	}
+	var rows []map[string]interface{}
+	var walk func(n *planNode, depth int)
+	walk = func(n *planNode, depth int) {
+		row := map[string]interface{}{"step": strings.Repeat("  ", depth) + n.step, "target": n.target, "detail": n.detail}
+		if query.Analyze {
+			row["rows"], row["time"] = n.rows, formatElapsed(n.elapsed)
+		}
+		rows = append(rows, row)
+		for _, child := range n.children {
+			walk(child, depth+1)
+		}
+	}
+	for _, child := range root.children {
+		walk(child, 0)
+	}
+	if query.Analyze {
+		rows = append(rows, map[string]interface{}{"step": "Total", "target": "", "detail": "", "rows": root.rows, "time": formatElapsed(root.elapsed)})
+	}
+	return rows, fields, nil
+}
+
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
+
+// describeScan says what scanRows reads for resDef within scope: the
+// resource and its GVR, and which part of the query is sent to the server.
+func describeScan(resDef *registry.ResourceDefinition, rel *relation, scope *parser.Query, refs []string) (target, detail string) {
+	if rel != nil {
+		return resDef.Name, "WITH relation, in memory"
+	}
+
+	var parts []string
+	switch {
+	case !resDef.Namespaced:
+		parts = append(parts, "cluster-scoped")
+	case scope.Namespace != "" && scope.Namespace != "*":
+		parts = append(parts, "namespace="+scope.Namespace+" (server-side)")
+	default:
+		parts = append(parts, "all namespaces")
+	}
+	if scope.FieldSelector != "" {
+		parts = append(parts, "fieldSelector="+scope.FieldSelector)
+	}
+	if len(scope.Labels) > 0 {
+		var labels []string
+		for k, v := range scope.Labels {
+			if v == "" {
+				labels = append(labels, k)
+			} else {
+				labels = append(labels, k+"="+v)
+			}
+		}
+		sort.Strings(labels)
+		parts = append(parts, "labelSelector="+strings.Join(labels, ","))
+	}
+
+	sc := scannerFor(resDef)
+	switch {
+	case sc.target != nil:
+		target = sc.target(scope)
+		parts = append(parts, sc.details...)
+	case scope.DescendantsOf != nil:
+		target = "descendants of " + scope.DescendantsOf.Resource + "/" + scope.DescendantsOf.Name
+	default:
+		gvr := resDef.GroupVersionResource
+		target = fmt.Sprintf("%s (%s)", resDef.Name, strings.TrimPrefix(gvr.GroupVersion().String()+"/"+gvr.Resource, "/"))
+		if needsMetrics(resDef, refs) {
+			parts = append(parts, "usage from metrics.k8s.io")
+		}
+		parts = append(parts, sc.details...)
+		for _, ref := range refs {
+			if strings.HasPrefix(ref, "root_owner") {
+				parts = append(parts, "owner chain")
+				break
+			}
+		}
+	}
+	return target, strings.Join(parts, ", ")
+}
+
+// describeJoin returns the ON conditions of join.
+func describeJoin(join parser.JoinClause) string {
+	conditions := join.Conditions
+	if len(conditions) == 0 && join.LeftField != "" {
+		conditions = []parser.JoinCondition{{LeftField: join.LeftField, RightField: join.RightField}}
+	}
+	on := make([]string, len(conditions))
+	for i, c := range conditions {
+		on[i] = c.LeftField + " = " + c.RightField
+	}
+	return "hash join ON " + strings.Join(on, " AND ")
+}
+
+// describeAggregation returns the aggregates and HAVING of query.
+func describeAggregation(query *parser.Query) string {
+	var parts []string
+	for _, agg := range query.Aggregates {
+		parts = append(parts, fmt.Sprintf("%s(%s) AS %s", agg.Function, agg.Field, agg.Alias))
+	}
+	detail := strings.Join(parts, ", ")
+	if query.Having != nil {
+		detail += " HAVING " + query.Having.String()
+	}
+	return strings.TrimSpace(detail)
+}
+
+// describeOrderBy returns the ORDER BY fields of query, e.g. "age DESC, name ASC".
+func describeOrderBy(orderBy []parser.OrderByField) string {
+	parts := make([]string, len(orderBy))
+	for i, o := range orderBy {
+		dir := "ASC"
+		if o.Descending {
+			dir = "DESC"
+		}
+		parts[i] = o.Field + " " + dir
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+)
+
+// planSteps returns the step, target and, with ANALYZE, rows of each plan row.
+func planSteps(rows []map[string]interface{}) [][]interface{} {
+	steps := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		steps[i] = []interface{}{row["step"], row["target"]}
+		if n, ok := row["rows"]; ok {
+			steps[i] = append(steps[i], n)
+		}
+	}
+	return steps
+}
+
+func TestExplain(t *testing.T) {
+	// Every list fails, so the plan must be built without listing
+	src := newGatedSource(0)
+	src.fail = "pods"
+	e := newFakeExecutor()
+	e.source = src
+
+	query := mustParse(t, "EXPLAIN p.name FROM pod p INNER JOIN service svc ON p.labels.app = svc.selector.app WHERE p.namespace = default AND p.name IN (SELECT name FROM pod WHERE status = Pending) ORDER BY p.name LIMIT 5")
+	rows, fields, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !reflect.DeepEqual(fields, []string{"step", "target", "detail"}) {
+		t.Errorf("fields = %v", fields)
+	}
+	want := [][]interface{}{
+		{"Fetch", "p: pod (v1/pods)"},
+		{"Fetch", "svc: service (v1/services)"},
+		{"Join", "INNER JOIN svc"},
+		{"Subquery", "p.name IN"},
+		{"  Fetch", "pod (v1/pods)"},
+		{"  Filter", "WHERE"},
+		{"  Project", "fields"},
+		{"Filter", "WHERE"},
+		{"Sort", "ORDER BY"},
+		{"Limit", "LIMIT 5"},
+		{"Project", "fields"},
+	}
+	if got := planSteps(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("plan =\n%v\nwant\n%v", got, want)
+	}
+	if got := rows[0]["detail"]; got != "namespace=default (server-side), in parallel" {
+		t.Errorf("primary fetch detail = %q", got)
+	}
+	if got := rows[2]["detail"]; got != "hash join ON p.labels.app = svc.selector.app" {
+		t.Errorf("join detail = %q", got)
+	}
+	if got := rows[7]["detail"]; got != "p.namespace = default AND p.name IN (SELECT name FROM pod WHERE status = Pending)" {
+		t.Errorf("filter detail = %q", got)
+	}
+}
+
+func TestExplainAnalyze(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+
+	query := mustParse(t, "EXPLAIN ANALYZE namespace, COUNT as pods FROM pod WHERE status = Running GROUP BY namespace HAVING pods > 0 ORDER BY namespace")
+	rows, fields, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !reflect.DeepEqual(fields, []string{"step", "target", "detail", "rows", "time"}) {
+		t.Errorf("fields = %v", fields)
+	}
+	want := [][]interface{}{
+		{"Fetch", "pod (v1/pods)", 4},
+		{"Filter", "WHERE", 3},
+		{"Aggregate", "GROUP BY namespace", 2},
+		{"Sort", "ORDER BY", 2},
+		{"Project", "fields", 2},
+		{"Total", "", 2},
+	}
+	if got := planSteps(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("plan =\n%v\nwant\n%v", got, want)
+	}
+	if got := rows[2]["detail"]; got != "COUNT(*) AS pods HAVING pods > 0" {
+		t.Errorf("aggregate detail = %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -9,95 +10,154 @@ import (
 	"github.com/bangmodtechnology/kselect/pkg/registry"
 )
 
-func (e *Executor) executeJoin(query *parser.Query) ([]map[string]interface{}, []string, error) {
-	// Get primary resource
-	primaryDef, ok := e.registry.Get(query.Resource)
-	if !ok {
-		return nil, nil, fmt.Errorf("unknown resource: %s", query.Resource)
-	}
+// joinSide is one resource taking part in a JOIN query: the primary FROM
+// resource or a joined resource, with the prefix its fields are stored under.
+type joinSide struct {
+	prefix    string
+	def       *registry.ResourceDefinition
+	rel       *relation // set when the side is a WITH relation
+	namespace string
+}
 
-	primaryItems, err := e.fetchResources(primaryDef, query)
+func (e *Executor) executeJoin(ctx context.Context, query *parser.Query) ([]map[string]interface{}, []string, error) {
+	sides, err := e.joinSides(ctx, query)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Extract primary rows
-	var primaryRows []map[string]interface{}
-	for _, item := range primaryItems {
-		row := make(map[string]interface{})
-		for fieldName, fieldDef := range primaryDef.Fields {
-			value := e.extractField(&item, fieldDef.JSONPath)
-			// Store with alias prefix if alias is set
-			prefix := query.Resource
-			if query.ResourceAlias != "" {
-				prefix = query.ResourceAlias
+	// Resolve field aliases against the side each reference belongs to
+	resolveQueryAliases(query, func(field string) string {
+		return resolveJoinFieldAlias(field, sides)
+	})
+	refs := queryFieldRefs(query)
+
+	// Fetch all sides at once: the primary with the query's own selectors,
+	// joined sides with their namespace only
+	scopes := make([]*parser.Query, len(sides))
+	steps := make([]*planNode, len(sides))
+	for i, side := range sides {
+		scopes[i] = query
+		if i > 0 {
+			scopes[i] = &parser.Query{
+				Namespace: side.namespace,
+				Labels:    make(map[string]string),
 			}
-			row[prefix+"."+fieldName] = value
-			row[fieldName] = value
 		}
-		primaryRows = append(primaryRows, row)
+		target, detail := describeScan(side.def, side.rel, scopes[i], sideFieldRefs(refs, side, sides))
+		steps[i] = addStep(ctx, "Fetch", side.prefix+": "+target, detail+", in parallel")
+	}
+	sideRows := make([][]map[string]interface{}, len(sides))
+	err = e.fetchAll(ctx, len(sides), func(ctx context.Context, e *Executor, i int) error {
+		rows, err := e.buildSideRows(steps[i].enter(ctx), scopes[i], sides[i], refs, sides)
+		sideRows[i] = rows
+		steps[i].done(len(rows))
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-
-	results := primaryRows
 
 	// Process each JOIN
-	for _, join := range query.Joins {
-		joinDef, ok := e.registry.Get(join.Resource)
-		if !ok {
-			return nil, nil, fmt.Errorf("unknown resource in JOIN: %s", join.Resource)
-		}
+	results := sideRows[0]
+	for i, join := range query.Joins {
+		step := addStep(ctx, "Join", fmt.Sprintf("%s JOIN %s", join.Type, join.Prefix()), describeJoin(join))
+		results = performJoin(results, sideRows[i+1], join)
+		step.done(len(results))
+	}
 
-		joinQuery := &parser.Query{
-			Namespace: query.Namespace,
-			Labels:    make(map[string]string),
-		}
-		joinItems, err := e.fetchResources(joinDef, joinQuery)
-		if err != nil {
-			return nil, nil, err
-		}
+	// Resolve output fields (expand * using registry)
+	fields := resolveJoinFields(query, e.registry)
 
-		// Extract join rows
-		var joinRows []map[string]interface{}
-		for _, item := range joinItems {
-			row := make(map[string]interface{})
-			for fieldName, fieldDef := range joinDef.Fields {
-				value := e.extractField(&item, fieldDef.JSONPath)
-				prefix := join.Resource
-				if join.Alias != "" {
-					prefix = join.Alias
-				}
-				row[prefix+"."+fieldName] = value
-				row[fieldName] = value
-			}
-			joinRows = append(joinRows, row)
-		}
+	return e.applyClauses(ctx, query, results, fields)
+}
 
-		results = performJoin(results, joinRows, join)
+// joinSides resolves the primary and joined resources of a JOIN query.
+// A joined side without its own namespace inherits the query namespace.
+func (e *Executor) joinSides(ctx context.Context, query *parser.Query) ([]joinSide, error) {
+	primaryDef, primaryRel, ok := e.lookupResource(ctx, query.Resource)
+	if !ok {
+		return nil, fmt.Errorf("unknown resource: %s", query.Resource)
 	}
+	sides := []joinSide{{prefix: query.Prefix(), def: primaryDef, rel: primaryRel, namespace: query.Namespace}}
 
-	// Resolve output fields (expand * using registry)
-	fields := resolveJoinFields(query, e.registry)
+	for i := range query.Joins {
+		join := &query.Joins[i]
+		joinDef, joinRel, ok := e.lookupResource(ctx, join.Resource)
+		if !ok {
+			return nil, fmt.Errorf("unknown resource in JOIN: %s", join.Resource)
+		}
+		ns := join.Namespace
+		if ns == "" {
+			ns = query.Namespace
+		}
+		sides = append(sides, joinSide{prefix: join.Prefix(), def: joinDef, rel: joinRel, namespace: ns})
+	}
+	return sides, nil
+}
 
-	// Apply WHERE conditions
-	if query.Conditions != nil {
-		var filtered []map[string]interface{}
-		for _, row := range results {
-			if query.Conditions.Evaluate(row) {
-				filtered = append(filtered, row)
-			}
+// buildSideRows scans the rows for one side of a JOIN within scope. Each value
+// is stored both under its bare field name and qualified with the side prefix
+// ("svc.name").
+func (e *Executor) buildSideRows(ctx context.Context, scope *parser.Query, side joinSide, refs []string, sides []joinSide) ([]map[string]interface{}, error) {
+	rows, err := e.scanRows(ctx, side.def, side.rel, scope, sideFieldRefs(refs, side, sides))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		prefixed := make(map[string]interface{}, len(row)*2)
+		for k, v := range row {
+			prefixed[side.prefix+"."+k] = v
+			prefixed[k] = v
 		}
-		results = filtered
+		rows[i] = prefixed
 	}
+	return rows, nil
+}
 
-	// Apply ORDER BY
-	if len(query.OrderBy) > 0 {
-		sortResults(results, query.OrderBy)
+// sideFieldRefs returns the field references that apply to side, with the
+// side prefix stripped. References qualified with another side are skipped;
+// unqualified references apply to every side.
+func sideFieldRefs(refs []string, side joinSide, sides []joinSide) []string {
+	var result []string
+	for _, ref := range refs {
+		owner, rest, ok := splitSidePrefix(ref, sides)
+		if !ok {
+			result = append(result, ref)
+		} else if owner.prefix == side.prefix {
+			result = append(result, rest)
+		}
 	}
+	return result
+}
 
-	// Apply LIMIT/OFFSET
-	results = applyLimitOffset(results, query.Limit, query.Offset)
+// splitSidePrefix splits a "prefix.field" reference when prefix names a JOIN side.
+func splitSidePrefix(field string, sides []joinSide) (joinSide, string, bool) {
+	prefix, rest, ok := strings.Cut(field, ".")
+	if !ok {
+		return joinSide{}, "", false
+	}
+	for _, side := range sides {
+		if side.prefix == prefix {
+			return side, rest, true
+		}
+	}
+	return joinSide{}, "", false
+}
 
-	return results, fields, nil
+// resolveJoinFieldAlias resolves a field alias in a JOIN query. Qualified
+// references ("svc.ns") resolve against their side; bare references resolve
+// against the first side that defines the field.
+func resolveJoinFieldAlias(field string, sides []joinSide) string {
+	if side, rest, ok := splitSidePrefix(field, sides); ok {
+		return side.prefix + "." + side.def.ResolveFieldAlias(rest)
+	}
+	for _, side := range sides {
+		if resolved := side.def.ResolveFieldAlias(field); resolved != field {
+			return resolved
+		}
+	}
+	return field
 }
 
 // performJoin uses a hash join strategy for O(n+m) performance.
@@ -274,22 +334,15 @@ func resolveJoinFields(query *parser.Query, reg *registry.Registry) []string {
 	var fields []string
 
 	// Primary resource fields
-	prefix := query.Resource
-	if query.ResourceAlias != "" {
-		prefix = query.ResourceAlias
-	}
 	if primaryDef, ok := reg.Get(query.Resource); ok {
-		fields = append(fields, allFieldNames(primaryDef, prefix)...)
+		fields = append(fields, allFieldNames(primaryDef, query.Prefix())...)
 	}
 
 	// Join resource fields
-	for _, join := range query.Joins {
-		jPrefix := join.Resource
-		if join.Alias != "" {
-			jPrefix = join.Alias
-		}
+	for i := range query.Joins {
+		join := &query.Joins[i]
 		if joinDef, ok := reg.Get(join.Resource); ok {
-			fields = append(fields, allFieldNames(joinDef, jPrefix)...)
+			fields = append(fields, allFieldNames(joinDef, join.Prefix())...)
 		}
 	}
 
@@ -0,0 +1,280 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+	"github.com/bangmodtechnology/kselect/pkg/source"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxLogLine is the longest log line read; longer lines fail the query.
+const maxLogLine = 1024 * 1024
+
+// podFields are the logs fields known before any line is read. Top-level
+// AND conditions on them decide which containers are read at all.
+var podFields = map[string]bool{"namespace": true, "pod": true, "node": true, "container": true, "labels": true}
+
+// scanLogs reads the logs of every container of the pods in scope, one row
+// per line, reading up to maxConcurrentFetches pods at a time. Lines are
+// filtered while they are read, and reading stops once LIMIT rows are found
+// if nothing after WHERE can change which rows those are.
+func (e *Executor) scanLogs(ctx context.Context, resDef *registry.ResourceDefinition, scope *parser.Query, refs []string) ([]map[string]interface{}, error) {
+	streamer, ok := e.source.(source.LogStreamer)
+	if !ok {
+		return nil, fmt.Errorf("reading logs is not supported by this source")
+	}
+	pods, err := e.logPods(ctx, resDef, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subquery values are resolved later, so such conditions are left to applyClauses
+	var where *parser.ConditionGroup
+	if scope.Conditions != nil && !hasSubQueries(scope.Conditions) {
+		where = scope.Conditions
+	}
+	containerWhere := containerConditions(where)
+	opts := corev1.PodLogOptions{Timestamps: true}
+	if since, ok := sinceTime(where); ok {
+		opts.SinceTime = &metav1.Time{Time: since}
+	}
+	limit := streamLimit(scope)
+
+	dynamicMapFields := collectDynamicMapFields(refs, resDef)
+	rawPaths := collectRawPaths(refs, resDef)
+
+	// Pods are read concurrently, each into its own rows so the result keeps
+	// pod order. LIMIT takes the first rows in pod order: once the pods up to
+	// some index are all read and hold LIMIT rows, the later ones are
+	// cancelled, and no pod reads more than LIMIT rows.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var limitReached atomic.Bool
+	var mu sync.Mutex
+	podRows := make([][]map[string]interface{}, len(pods))
+	finished := make([]bool, len(pods))
+	// Only the reader of pod i writes podRows[i], and finish reads only the
+	// rows of pods that are done
+	finish := func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		finished[i] = true
+		n := 0
+		for j := range pods {
+			if !finished[j] {
+				return
+			}
+			if n += len(podRows[j]); limit > 0 && n >= limit {
+				limitReached.Store(true)
+				cancel()
+				return
+			}
+		}
+	}
+	err = e.fetchAll(ctx, len(pods), func(ctx context.Context, e *Executor, i int) error {
+		pod := pods[i]
+		nodeName, _, _ := unstructured.NestedString(pod.Object, "spec", "nodeName")
+		labels, _, _ := unstructured.NestedFieldNoCopy(pod.Object, "metadata", "labels")
+		for _, container := range podContainerNames(pod) {
+			if limitReached.Load() || limit > 0 && len(podRows[i]) >= limit {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			obj := map[string]interface{}{
+				"metadata":  map[string]interface{}{"namespace": pod.GetNamespace(), "labels": labels},
+				"pod":       map[string]interface{}{"name": pod.GetName(), "node": nodeName},
+				"container": container,
+			}
+			line := unstructured.Unstructured{Object: obj}
+			if containerWhere != nil && !containerWhere.Evaluate(e.buildRow(&line, resDef, dynamicMapFields, rawPaths)) {
+				continue
+			}
+
+			containerOpts := opts
+			containerOpts.Container = container
+			stream, err := streamer.Logs(ctx, pod.GetNamespace(), pod.GetName(), &containerOpts)
+			if apierrors.IsBadRequest(err) {
+				// The container has not started yet
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read logs of %s/%s: %w", pod.GetName(), container, err)
+			}
+
+			scanner := bufio.NewScanner(stream)
+			scanner.Buffer(make([]byte, 64*1024), maxLogLine)
+			for scanner.Scan() && (limit == 0 || len(podRows[i]) < limit) {
+				ts, text := splitTimestamp(scanner.Text())
+				obj["ts"], obj["line"] = ts, text
+				row := e.buildRow(&line, resDef, dynamicMapFields, rawPaths)
+				if where != nil && !where.Evaluate(row) {
+					continue
+				}
+				podRows[i] = append(podRows[i], row)
+			}
+			err = scanner.Err()
+			stream.Close()
+			if err != nil && !limitReached.Load() {
+				return fmt.Errorf("failed to read logs of %s/%s: %w", pod.GetName(), container, err)
+			}
+		}
+		finish(i)
+		return nil
+	})
+	// Streams cut short by LIMIT fail with the cancelled context
+	if err != nil && !limitReached.Load() {
+		return nil, err
+	}
+
+	var rows []map[string]interface{}
+	for _, r := range podRows {
+		rows = append(rows, r...)
+	}
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
+
+// logPods lists the pods to read logs from: the rows of the logs(query)
+// query if there is one, otherwise the pods in scope.
+func (e *Executor) logPods(ctx context.Context, resDef *registry.ResourceDefinition, scope *parser.Query) ([]unstructured.Unstructured, error) {
+	if scope.LogsOf == nil {
+		return e.fetchResources(ctx, resDef, scope)
+	}
+
+	inner := scope.LogsOf
+	if def, _, ok := e.lookupResource(ctx, inner.Resource); !ok || def.Name != "pod" {
+		return nil, fmt.Errorf("logs() query must select from pod, not %s", inner.Resource)
+	}
+	if inner.Namespace == "" {
+		inner.Namespace = scope.Namespace
+	}
+	results, _, err := e.execute(ctx, inner)
+	if err != nil {
+		return nil, fmt.Errorf("logs() query error: %w", err)
+	}
+	selected := make(map[string]bool, len(results))
+	for _, row := range results {
+		selected[fmt.Sprintf("%v/%v", row["namespace"], row["name"])] = true
+	}
+
+	pods, err := e.fetchResources(ctx, resDef, &parser.Query{Namespace: inner.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	var matched []unstructured.Unstructured
+	for _, pod := range pods {
+		if selected[pod.GetNamespace()+"/"+pod.GetName()] {
+			matched = append(matched, pod)
+		}
+	}
+	return matched, nil
+}
+
+// podContainerNames returns the init and regular containers of pod in order.
+func podContainerNames(pod unstructured.Unstructured) []string {
+	var names []string
+	for _, key := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", key)
+		for _, c := range containers {
+			if spec, ok := c.(map[string]interface{}); ok {
+				names = append(names, fmt.Sprint(spec["name"]))
+			}
+		}
+	}
+	return names
+}
+
+// splitTimestamp splits a line read with Timestamps set into its RFC 3339
+// timestamp and text. Lines without one have a nil timestamp.
+func splitTimestamp(s string) (interface{}, string) {
+	ts, text, ok := strings.Cut(s, " ")
+	if !ok {
+		ts, text = s, ""
+	}
+	if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+		return nil, s
+	}
+	return ts, text
+}
+
+// containerConditions returns the top-level AND conditions of where that
+// read only podFields, or nil if there are none.
+func containerConditions(where *parser.ConditionGroup) *parser.ConditionGroup {
+	if where == nil || where.LogicalOperator != parser.LogicalAnd {
+		return nil
+	}
+	group := &parser.ConditionGroup{LogicalOperator: parser.LogicalAnd}
+	for _, cond := range where.Conditions {
+		field, _, _ := strings.Cut(cond.Field, ".")
+		if podFields[field] {
+			group.Conditions = append(group.Conditions, cond)
+		}
+	}
+	if len(group.Conditions) == 0 {
+		return nil
+	}
+	return group
+}
+
+// sinceTime returns the latest lower bound on ts in the top-level AND
+// conditions of where, so older lines are not sent at all.
+func sinceTime(where *parser.ConditionGroup) (time.Time, bool) {
+	var since time.Time
+	if where == nil || where.LogicalOperator != parser.LogicalAnd {
+		return since, false
+	}
+	for _, cond := range where.Conditions {
+		if cond.Field != "ts" || cond.Operator != parser.OpGreaterThan && cond.Operator != parser.OpGreaterEqual {
+			continue
+		}
+		if t, ok := parser.ParseTimeValue(cond.Value); ok && t.After(since) {
+			since = t
+		}
+	}
+	return since, !since.IsZero()
+}
+
+// streamLimit returns how many matching rows a query needs from its source
+// (LIMIT plus OFFSET), or 0 when it needs all of them: when rows are
+// aggregated, deduplicated or sorted first.
+func streamLimit(query *parser.Query) int {
+	if query.Limit == 0 || len(query.Aggregates) > 0 || len(query.GroupBy) > 0 ||
+		query.Distinct || len(query.OrderBy) > 0 || len(query.Joins) > 0 || len(query.SetOps) > 0 {
+		return 0
+	}
+	if query.Conditions != nil && hasSubQueries(query.Conditions) {
+		return 0
+	}
+	return query.Limit + query.Offset
+}
+
+// hasSubQueries reports whether any condition in group has a subquery.
+func hasSubQueries(group *parser.ConditionGroup) bool {
+	for _, cond := range group.Conditions {
+		if cond.SubQuery != nil {
+			return true
+		}
+	}
+	for _, sub := range group.SubGroups {
+		if hasSubQueries(sub) {
+			return true
+		}
+	}
+	return false
+}
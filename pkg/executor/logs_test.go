@@ -0,0 +1,201 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+	"github.com/bangmodtechnology/kselect/pkg/source"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newLogPod(namespace, name, app string, containers ...string) *unstructured.Unstructured {
+	var specs []interface{}
+	for _, c := range containers {
+		specs = append(specs, map[string]interface{}{"name": c})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace, "labels": map[string]interface{}{"app": app}},
+		"spec":       map[string]interface{}{"nodeName": "node-1", "containers": specs},
+	}}
+}
+
+// fakeLogs serves container logs keyed by namespace/pod/container and
+// records the containers read. The logs of blocked pods are only served
+// once the read is cancelled, and then fail.
+type fakeLogs struct {
+	logs    map[string][]string
+	blocked map[string]bool
+
+	mu    sync.Mutex
+	reads []string
+	opts  []*corev1.PodLogOptions
+}
+
+func (f *fakeLogs) Logs(ctx context.Context, namespace, pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	if f.blocked[pod] {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil, fmt.Errorf("logs of %s were not cancelled", pod)
+		}
+	}
+	key := namespace + "/" + pod + "/" + opts.Container
+	lines, ok := f.logs[key]
+	if !ok {
+		return nil, apierrors.NewBadRequest("container " + opts.Container + " is waiting to start")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reads = append(f.reads, key)
+	f.opts = append(f.opts, opts)
+	return io.NopCloser(strings.NewReader(strings.Join(lines, "\n") + "\n")), nil
+}
+
+// logsSource serves pods from memory and their logs from fakeLogs.
+type logsSource struct {
+	*source.Memory
+	*fakeLogs
+}
+
+func newLogsExecutor(t *testing.T) (*Executor, *fakeLogs) {
+	t.Helper()
+	ts := func(ago time.Duration) string {
+		return time.Now().Add(-ago).UTC().Format(time.RFC3339Nano) + " "
+	}
+	logs := &fakeLogs{logs: map[string][]string{
+		"prod/web-1/app":     {ts(3 * time.Hour), ts(2*time.Hour) + "panic: old", ts(30*time.Minute) + "panic: nil map", ts(time.Minute) + "ok"},
+		"prod/web-1/sidecar": {ts(10*time.Minute) + "proxy ready"},
+		"prod/api-1/app":     {ts(20*time.Minute) + "panic: timeout", ts(10*time.Minute) + "panic: timeout", "no timestamp"},
+		"default/other/app":  {ts(time.Minute) + "panic: elsewhere"},
+	}}
+	pods := fakeSource(
+		newLogPod("prod", "web-1", "web", "app", "sidecar"),
+		newLogPod("prod", "api-1", "api", "app"),
+		newLogPod("prod", "pending", "api", "app"),
+		newLogPod("default", "other", "web", "app"),
+	)
+	return &Executor{
+		source:           logsSource{pods, logs},
+		registry:         registry.GetGlobalRegistry(),
+		CurrentNamespace: "default",
+	}, logs
+}
+
+func TestExecuteLogs(t *testing.T) {
+	e, logs := newLogsExecutor(t)
+	results, fields, err := e.Execute(mustParse(t, "SELECT pod, count FROM logs WHERE namespace=prod AND line LIKE '%panic%' AND ts > now()-1h GROUP BY pod ORDER BY pod"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(fields) != 2 || fields[1] != "count" {
+		t.Errorf("Expected pod, count columns, got %v", fields)
+	}
+	if len(results) != 2 || results[0]["pod"] != "api-1" || results[0]["count"] != 2 || results[1]["pod"] != "web-1" || results[1]["count"] != 1 {
+		t.Errorf("Unexpected panic counts: %v", results)
+	}
+	// Lines older than the ts bound are not requested
+	if len(logs.opts) == 0 || logs.opts[0].SinceTime == nil || !logs.opts[0].Timestamps {
+		t.Errorf("Expected SinceTime and Timestamps in log options, got %+v", logs.opts)
+	}
+
+	// Pod-level conditions decide which containers are read
+	logs.reads = nil
+	results, _, err = e.Execute(mustParse(t, "ts, container, line FROM logs WHERE namespace = prod AND pod = web-1 AND container = sidecar"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(logs.reads) != 1 || len(results) != 1 || results[0]["line"] != "proxy ready" {
+		t.Errorf("Expected only the sidecar log, read %v got %v", logs.reads, results)
+	}
+}
+
+func TestExecuteLogsOfQuery(t *testing.T) {
+	e, logs := newLogsExecutor(t)
+	results, _, err := e.Execute(mustParse(t, "ts, pod, line FROM logs(pod WHERE namespace = prod AND labels.app = api)"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	// api-1 has three lines; the pending pod's container has not started
+	if len(logs.reads) != 1 || logs.reads[0] != "prod/api-1/app" || len(results) != 3 {
+		t.Fatalf("Expected the 3 lines of api-1, read %v got %v", logs.reads, results)
+	}
+	if results[2]["ts"] != nil || results[2]["line"] != "no timestamp" {
+		t.Errorf("Expected a line without timestamp to be kept whole, got %v", results[2])
+	}
+
+	if _, _, err := e.Execute(mustParse(t, "line FROM logs(name FROM node)")); err == nil || !strings.Contains(err.Error(), "must select from pod") {
+		t.Errorf("Expected pod-only error, got %v", err)
+	}
+}
+
+func TestExecuteLogsLimit(t *testing.T) {
+	e, logs := newLogsExecutor(t)
+	results, _, err := e.Execute(mustParse(t, "pod, line FROM logs WHERE namespace = prod AND line LIKE 'panic%' LIMIT 1"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 row, got %v", results)
+	}
+
+	// ORDER BY needs every row before LIMIT applies
+	logs.reads = nil
+	if _, _, err := e.Execute(mustParse(t, "pod, line FROM logs WHERE namespace = prod ORDER BY ts DESC LIMIT 1")); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(logs.reads) != 3 {
+		t.Errorf("Expected all 3 started containers to be read, read %v", logs.reads)
+	}
+}
+
+func TestExecuteLogsConcurrent(t *testing.T) {
+	logs := &fakeLogs{logs: map[string][]string{}}
+	var pods []runtime.Object
+	for i := 0; i < 3*maxConcurrentFetches; i++ {
+		name := fmt.Sprintf("web-%02d", i)
+		pods = append(pods, newLogPod("prod", name, "web", "app"))
+		logs.logs["prod/"+name+"/app"] = []string{"panic: " + name}
+	}
+	e := &Executor{source: logsSource{fakeSource(pods...), logs}, registry: registry.GetGlobalRegistry()}
+
+	// Without LIMIT every pod is read, and rows keep pod order
+	results, _, err := e.Execute(mustParse(t, "pod, line FROM logs WHERE namespace = prod"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != len(pods) || !sort.SliceIsSorted(results, func(i, j int) bool {
+		return results[i]["pod"].(string) < results[j]["pod"].(string)
+	}) {
+		t.Errorf("Expected one line per pod in pod order, got %v", results)
+	}
+
+	// LIMIT returns the first lines in pod order every time, and cancels
+	// the later pods once the earlier ones hold enough lines
+	logs.blocked = make(map[string]bool)
+	for i := maxConcurrentFetches; i < len(pods); i++ {
+		logs.blocked[fmt.Sprintf("web-%02d", i)] = true
+	}
+	for run := 0; run < 10; run++ {
+		results, _, err = e.Execute(mustParse(t, "pod, line FROM logs WHERE namespace = prod LIMIT 2"))
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if len(results) != 2 || results[0]["pod"] != "web-00" || results[1]["pod"] != "web-01" {
+			t.Fatalf("Expected the lines of web-00 and web-01, got %v", results)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// metricsGroupVersion is the API served by metrics-server.
+var metricsGroupVersion = schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+
+// needsMetrics reports whether any of refs reads usage from the metrics API.
+func needsMetrics(resDef *registry.ResourceDefinition, refs []string) bool {
+	if resDef.Metrics == "" {
+		return false
+	}
+	for _, ref := range refs {
+		if resDef.Fields[ref].Metrics {
+			return true
+		}
+	}
+	return false
+}
+
+// attachMetrics joins PodMetrics or NodeMetrics to items by namespace and
+// name, storing each object's metrics under .metrics. Objects without
+// metrics (e.g. pods that are not running) are left as they are.
+func (e *Executor) attachMetrics(ctx context.Context, resDef *registry.ResourceDefinition, items []unstructured.Unstructured, namespace string) error {
+	if !resDef.Namespaced {
+		namespace = ""
+	}
+	list, err := e.list(ctx, metricsGroupVersion.WithResource(resDef.Metrics), namespace, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to read %s metrics (is metrics-server installed?): %w", resDef.Name, err)
+	}
+
+	usage := make(map[string]map[string]interface{}, len(list.Items))
+	for _, m := range list.Items {
+		usage[m.GetNamespace()+"/"+m.GetName()] = m.Object
+	}
+	for i := range items {
+		if m, ok := usage[items[i].GetNamespace()+"/"+items[i].GetName()]; ok {
+			items[i].Object["metrics"] = m
+		}
+	}
+	return nil
+}
+
+// containerItems turns pods into one object per container, laid out as the
+// container resource describes: metadata, pod, init, spec, status and metrics.
+func containerItems(pods []unstructured.Unstructured) []unstructured.Unstructured {
+	var items []unstructured.Unstructured
+	for _, pod := range pods {
+		statuses := make(map[string]interface{})
+		for _, key := range []string{"initContainerStatuses", "containerStatuses"} {
+			list, _, _ := unstructured.NestedSlice(pod.Object, "status", key)
+			for _, s := range list {
+				if status, ok := s.(map[string]interface{}); ok {
+					statuses[fmt.Sprint(status["name"])] = status
+				}
+			}
+		}
+		usage := make(map[string]interface{})
+		metricsContainers, _, _ := unstructured.NestedSlice(pod.Object, "metrics", "containers")
+		for _, c := range metricsContainers {
+			if m, ok := c.(map[string]interface{}); ok {
+				usage[fmt.Sprint(m["name"])] = m
+			}
+		}
+		nodeName, _, _ := unstructured.NestedString(pod.Object, "spec", "nodeName")
+		created, _, _ := unstructured.NestedFieldNoCopy(pod.Object, "metadata", "creationTimestamp")
+		labels, _, _ := unstructured.NestedFieldNoCopy(pod.Object, "metadata", "labels")
+
+		for _, key := range []string{"initContainers", "containers"} {
+			containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", key)
+			for _, c := range containers {
+				spec, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name := fmt.Sprint(spec["name"])
+				obj := map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name":              name,
+						"namespace":         pod.GetNamespace(),
+						"creationTimestamp": created,
+						"labels":            labels,
+					},
+					"pod":  map[string]interface{}{"name": pod.GetName(), "node": nodeName},
+					"init": key == "initContainers",
+					"spec": spec,
+				}
+				if status, ok := statuses[name]; ok {
+					obj["status"] = status
+				}
+				if m, ok := usage[name]; ok {
+					obj["metrics"] = m
+				}
+				items = append(items, unstructured.Unstructured{Object: obj})
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,187 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+	"github.com/bangmodtechnology/kselect/pkg/source"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var (
+	nodeGVR        = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	podMetricsGVR  = metricsGroupVersion.WithResource("pods")
+	nodeMetricsGVR = metricsGroupVersion.WithResource("nodes")
+)
+
+func newMetricsPod(name string, containers map[string]string) *unstructured.Unstructured {
+	var specs []interface{}
+	for _, c := range []string{"app", "sidecar"} {
+		if req, ok := containers[c]; ok {
+			specs = append(specs, map[string]interface{}{
+				"name":      c,
+				"image":     c + ":1",
+				"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": req, "memory": "256Mi"}},
+			})
+		}
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec":       map[string]interface{}{"nodeName": "node-1", "containers": specs},
+		"status": map[string]interface{}{
+			"phase": "Running",
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"name": "app", "ready": true, "restartCount": int64(3), "state": map[string]interface{}{"running": map[string]interface{}{}}},
+			},
+		},
+	}}
+}
+
+// newMetricsExecutor serves pods and nodes plus their metrics from a fake
+// metrics server. Metrics are created through their GVR, since the fake
+// client cannot guess the resource of the PodMetrics kind.
+func newMetricsExecutor(t *testing.T, withMetrics bool) (*Executor, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	listKinds := map[schema.GroupVersionResource]string{
+		podGVR:         "PodList",
+		nodeGVR:        "NodeList",
+		podMetricsGVR:  "PodMetricsList",
+		nodeMetricsGVR: "NodeMetricsList",
+	}
+	node := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Node",
+		"metadata":   map[string]interface{}{"name": "node-1"},
+		"status":     map[string]interface{}{"allocatable": map[string]interface{}{"cpu": "4", "memory": "8Gi"}},
+	}}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		newMetricsPod("web", map[string]string{"app": "500m", "sidecar": "100m"}),
+		newMetricsPod("batch", map[string]string{"app": "1"}),
+		node,
+	)
+
+	if withMetrics {
+		usage := func(cpu, memory string) map[string]interface{} {
+			return map[string]interface{}{"cpu": cpu, "memory": memory}
+		}
+		metrics := []struct {
+			gvr schema.GroupVersionResource
+			obj map[string]interface{}
+		}{
+			{podMetricsGVR, map[string]interface{}{
+				"kind":     "PodMetrics",
+				"metadata": map[string]interface{}{"name": "web", "namespace": "default"},
+				"containers": []interface{}{
+					map[string]interface{}{"name": "app", "usage": usage("250000000n", "512Mi")},
+					map[string]interface{}{"name": "sidecar", "usage": usage("50000000n", "64Mi")},
+				},
+			}},
+			{podMetricsGVR, map[string]interface{}{
+				"kind":       "PodMetrics",
+				"metadata":   map[string]interface{}{"name": "batch", "namespace": "default"},
+				"containers": []interface{}{map[string]interface{}{"name": "app", "usage": usage("900m", "1Gi")}},
+			}},
+			{nodeMetricsGVR, map[string]interface{}{
+				"kind":     "NodeMetrics",
+				"metadata": map[string]interface{}{"name": "node-1"},
+				"usage":    usage("1", "2Gi"),
+			}},
+		}
+		for _, m := range metrics {
+			m.obj["apiVersion"] = metricsGroupVersion.String()
+			obj := &unstructured.Unstructured{Object: m.obj}
+			res := client.Resource(m.gvr)
+			var err error
+			if ns := obj.GetNamespace(); ns != "" {
+				_, err = res.Namespace(ns).Create(context.TODO(), obj, metav1.CreateOptions{})
+			} else {
+				_, err = res.Create(context.TODO(), obj, metav1.CreateOptions{})
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return &Executor{
+		source:           source.NewLive(client, nil, nil),
+		registry:         registry.GetGlobalRegistry(),
+		CurrentNamespace: "default",
+	}, client
+}
+
+func TestExecutePodMetrics(t *testing.T) {
+	e, _ := newMetricsExecutor(t, true)
+	results, _, err := e.Execute(mustParse(t, "name, cpu.usage, mem.usage, cpu.usage-pct-of-req FROM pod WHERE namespace = default ORDER BY mem.usage DESC"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 rows, got %v", results)
+	}
+	batch, web := results[0], results[1]
+	if batch["name"] != "batch" || batch["mem.usage"] != int64(1024) || batch["cpu.usage-pct-of-req"] != int64(90) {
+		t.Errorf("Unexpected batch row: %v", batch)
+	}
+	if web["cpu.usage"] != int64(300) || web["mem.usage"] != int64(576) || web["cpu.usage-pct-of-req"] != int64(50) {
+		t.Errorf("Unexpected web row: %v", web)
+	}
+}
+
+func TestExecuteNodeAndContainerMetrics(t *testing.T) {
+	e, _ := newMetricsExecutor(t, true)
+	results, _, err := e.Execute(mustParse(t, "name, cpu.usage, cpu.usage-pct, mem.usage-pct FROM node"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 1 || results[0]["cpu.usage"] != int64(1000) || results[0]["cpu.usage-pct"] != int64(25) || results[0]["mem.usage-pct"] != int64(25) {
+		t.Errorf("Unexpected node rows: %v", results)
+	}
+
+	results, _, err = e.Execute(mustParse(t, "pod, name, image, restarts, state, cpu.usage, cpu.usage-pct-of-req FROM container WHERE namespace = default AND pod = web ORDER BY name"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 containers, got %v", results)
+	}
+	app, sidecar := results[0], results[1]
+	if app["name"] != "app" || app["image"] != "app:1" || app["restarts"] != int64(3) || app["state"] != "Running" || app["cpu.usage"] != int64(250) || app["cpu.usage-pct-of-req"] != int64(50) {
+		t.Errorf("Unexpected app container: %v", app)
+	}
+	if sidecar["name"] != "sidecar" || sidecar["cpu.usage"] != int64(50) || sidecar["state"] != nil {
+		t.Errorf("Unexpected sidecar container: %v", sidecar)
+	}
+}
+
+func TestExecuteMetricsUnavailable(t *testing.T) {
+	e, client := newMetricsExecutor(t, false)
+
+	// Queries that do not read usage never touch the metrics API
+	if _, _, err := e.Execute(mustParse(t, "name FROM pod WHERE namespace = default")); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// Without metrics-server the API group is not served
+	client.PrependReactor("list", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Group != metricsGroupVersion.Group {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewNotFound(action.GetResource().GroupResource(), "")
+	})
+	_, _, err := e.Execute(mustParse(t, "name, cpu.usage FROM pod WHERE namespace = default"))
+	if err == nil || !strings.Contains(err.Error(), "metrics-server") {
+		t.Errorf("Expected a metrics-server error, got %v", err)
+	}
+}
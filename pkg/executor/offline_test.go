@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bangmodtechnology/kselect/pkg/manifest"
+	"github.com/bangmodtechnology/kselect/pkg/source"
+)
+
+const offlineManifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+  labels: {app: web}
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.27
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+  labels: {app: worker}
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: other
+spec:
+  replicas: 2
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: gizmo
+  namespace: shop
+spec:
+  size: 7
+`
+
+func newOfflineExecutor(t *testing.T) *Executor {
+	t.Helper()
+	objects, err := manifest.Read(strings.NewReader(offlineManifests))
+	if err != nil {
+		t.Fatalf("manifest.Read() error = %v", err)
+	}
+	return New(source.NewMemory(objects))
+}
+
+func TestExecuteOffline(t *testing.T) {
+	exec := newOfflineExecutor(t)
+
+	tests := []struct {
+		name      string
+		sql       string
+		namespace string
+		want      string
+	}{
+		{"all namespaces", "name FROM deployment ORDER BY name", "", "api,web,worker"},
+		// worker has no namespace, so it would be created in any of them
+		{"namespace", "name FROM deployment ORDER BY name", "shop", "web,worker"},
+		{"where", "name FROM deployment WHERE replicas > 1 ORDER BY name", "", "api,web"},
+		{"labels", "name FROM deployment WHERE labels.app = worker", "", "worker"},
+		{"discovered kind", "name FROM widgets", "", "gizmo"},
+		{"no objects", "name FROM service", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := mustParse(t, tt.sql)
+			query.Namespace = tt.namespace
+			results, _, err := exec.Execute(query)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			var got []string
+			for _, row := range results {
+				got = append(got, row["name"].(string))
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("names = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteOfflineLogs(t *testing.T) {
+	_, _, err := newOfflineExecutor(t).Execute(mustParse(t, "line FROM logs"))
+	if err == nil {
+		t.Error("Execute() FROM logs offline succeeded, want an error")
+	}
+}
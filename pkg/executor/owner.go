@@ -0,0 +1,255 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxOwnerDepth bounds owner chain walks so a reference cycle cannot loop forever.
+const maxOwnerDepth = 10
+
+// descendantResources are the resources scanned for children when walking
+// down from a root object: Deployment → ReplicaSet → Pod, CronJob → Job → Pod,
+// and StatefulSet/DaemonSet → Pod.
+var descendantResources = []string{"replicaset", "job", "pod"}
+
+// objectKey identifies an object by namespace, kind and name.
+type objectKey struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+func keyOf(obj *unstructured.Unstructured) objectKey {
+	return objectKey{namespace: obj.GetNamespace(), kind: obj.GetKind(), name: obj.GetName()}
+}
+
+// ownerIndex maps objects to their controlling ownerReference so owner chains
+// can be walked in memory once the owners have been fetched.
+type ownerIndex struct {
+	e         *Executor
+	namespace string
+	owners    map[objectKey]*metav1.OwnerReference // nil: object known, has no owner
+	listed    map[string]bool                      // owner kinds already fetched
+}
+
+func (e *Executor) newOwnerIndex(namespace string) *ownerIndex {
+	return &ownerIndex{
+		e:         e,
+		namespace: namespace,
+		owners:    make(map[objectKey]*metav1.OwnerReference),
+		listed:    make(map[string]bool),
+	}
+}
+
+func (idx *ownerIndex) add(items []unstructured.Unstructured) {
+	for i := range items {
+		idx.owners[keyOf(&items[i])] = controllerRef(&items[i])
+	}
+}
+
+// resolve fetches every owner kind referenced by indexed objects, repeating
+// until each chain ends at an object without owners or at a kind the
+// registry does not know.
+func (idx *ownerIndex) resolve(ctx context.Context) error {
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		var pending []string
+		for _, ref := range idx.owners {
+			if ref != nil && !idx.listed[ref.Kind] {
+				idx.listed[ref.Kind] = true
+				pending = append(pending, ref.Kind)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		sort.Strings(pending)
+
+		for _, kind := range pending {
+			def, ok := idx.e.registry.Get(strings.ToLower(kind))
+			if !ok || def.GroupVersionResource.Resource == "" {
+				continue
+			}
+			items, err := idx.e.fetchResources(ctx, def, &parser.Query{Namespace: idx.namespace})
+			if err != nil {
+				return fmt.Errorf("failed to resolve owners: %w", err)
+			}
+			idx.add(items)
+		}
+	}
+	return nil
+}
+
+// ownerKey returns the key of the object ref points at. Owners share the
+// child's namespace unless the registry knows the owner kind as cluster-scoped.
+func (idx *ownerIndex) ownerKey(child objectKey, ref *metav1.OwnerReference) objectKey {
+	ns := child.namespace
+	if def, ok := idx.e.registry.Get(strings.ToLower(ref.Kind)); ok && !def.Namespaced {
+		ns = ""
+	}
+	return objectKey{namespace: ns, kind: ref.Kind, name: ref.Name}
+}
+
+// root walks up the owner chain from key. It returns false if the object has no owner.
+func (idx *ownerIndex) root(key objectKey) (objectKey, bool) {
+	found := false
+	for i := 0; i < maxOwnerDepth; i++ {
+		ref := idx.owners[key]
+		if ref == nil {
+			break
+		}
+		key = idx.ownerKey(key, ref)
+		found = true
+	}
+	return key, found
+}
+
+// controllerRef returns the ownerReference marked as controller, falling back
+// to the first reference. Returns nil if the object has no owners.
+func controllerRef(obj *unstructured.Unstructured) *metav1.OwnerReference {
+	refs := obj.GetOwnerReferences()
+	if len(refs) == 0 {
+		return nil
+	}
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	return &refs[0]
+}
+
+// addOwnerFields fills the virtual owner fields referenced by the query into
+// rows (aligned with items). root_owner needs the whole owner chain, so the
+// owners are fetched and indexed first; owner.* reads ownerReferences only.
+func (e *Executor) addOwnerFields(ctx context.Context, rows []map[string]interface{}, items []unstructured.Unstructured, refs []string, namespace string) error {
+	wantOwner, wantRoot := false, false
+	for _, ref := range refs {
+		if registry.IsOwnerField(ref) {
+			wantOwner = true
+			if strings.HasPrefix(ref, "root_owner") {
+				wantRoot = true
+			}
+		}
+	}
+	if !wantOwner {
+		return nil
+	}
+
+	var idx *ownerIndex
+	if wantRoot {
+		idx = e.newOwnerIndex(namespace)
+		idx.add(items)
+		if err := idx.resolve(ctx); err != nil {
+			return err
+		}
+	}
+
+	for i := range items {
+		row := rows[i]
+		row["owner.kind"], row["owner.name"] = nil, nil
+		if ref := controllerRef(&items[i]); ref != nil {
+			row["owner.kind"] = ref.Kind
+			row["owner.name"] = ref.Name
+		}
+
+		if idx == nil {
+			continue
+		}
+		row["root_owner"], row["root_owner.kind"], row["root_owner.name"] = nil, nil, nil
+		if root, ok := idx.root(keyOf(&items[i])); ok {
+			row["root_owner"] = root.kind + "/" + root.name
+			row["root_owner.kind"] = root.kind
+			row["root_owner.name"] = root.name
+		}
+	}
+	return nil
+}
+
+// fetchDescendants returns every object owned, directly or transitively, by
+// the object ref names, in breadth-first order, along with each object's
+// distance from the root (1 for direct children). When namespace is empty,
+// every object named ref.Name is a root, walked in namespace order, so
+// same-named objects in other namespaces are not left out.
+func (e *Executor) fetchDescendants(ctx context.Context, ref *parser.ObjectRef, namespace string) ([]unstructured.Unstructured, []int, error) {
+	rootDef, _, ok := e.lookupResource(ctx, ref.Resource)
+	if !ok || rootDef.GroupVersionResource.Resource == "" {
+		return nil, nil, fmt.Errorf("unknown resource in DESCENDANTS OF: %s", ref.Resource)
+	}
+
+	candidates, err := e.fetchResources(ctx, rootDef, &parser.Query{Namespace: namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+	var roots []*unstructured.Unstructured
+	for i := range candidates {
+		if candidates[i].GetName() == ref.Name {
+			roots = append(roots, &candidates[i])
+		}
+	}
+	if len(roots) == 0 {
+		return nil, nil, fmt.Errorf("%s/%s not found", ref.Resource, ref.Name)
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		return roots[i].GetNamespace() < roots[j].GetNamespace()
+	})
+
+	// A single root only needs its own namespace scanned
+	childNamespace := namespace
+	if len(roots) == 1 {
+		childNamespace = roots[0].GetNamespace()
+	}
+
+	// Index candidate children by the UIDs of their owners
+	children := make(map[types.UID][]unstructured.Unstructured)
+	for _, name := range descendantResources {
+		def, ok := e.registry.Get(name)
+		if !ok {
+			continue
+		}
+		items, err := e.fetchResources(ctx, def, &parser.Query{Namespace: childNamespace})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, item := range items {
+			for _, owner := range item.GetOwnerReferences() {
+				children[owner.UID] = append(children[owner.UID], item)
+			}
+		}
+	}
+
+	type pending struct {
+		uid   types.UID
+		depth int
+	}
+	var items []unstructured.Unstructured
+	var depths []int
+	seen := make(map[types.UID]bool)
+	for _, root := range roots {
+		seen[root.GetUID()] = true
+		queue := []pending{{uid: root.GetUID(), depth: 1}}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			for _, child := range children[cur.uid] {
+				if seen[child.GetUID()] {
+					continue
+				}
+				seen[child.GetUID()] = true
+				items = append(items, child)
+				depths = append(depths, cur.depth)
+				queue = append(queue, pending{uid: child.GetUID(), depth: cur.depth + 1})
+			}
+		}
+	}
+	return items, depths, nil
+}
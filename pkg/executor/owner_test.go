@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// newOwned builds an object of kind owned by ownerKind/ownerName (no owner if ownerKind is empty).
+func newOwned(apiVersion, kind, name, ownerKind, ownerName string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+			"uid":       kind + "-" + name,
+		},
+	}}
+	if ownerKind != "" {
+		obj.Object["metadata"].(map[string]interface{})["ownerReferences"] = []interface{}{
+			map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       ownerKind,
+				"name":       ownerName,
+				"uid":        ownerKind + "-" + ownerName,
+				"controller": true,
+			},
+		}
+	}
+	return obj
+}
+
+func ownerFixtures() []runtime.Object {
+	return []runtime.Object{
+		newOwned("apps/v1", "Deployment", "web", "", ""),
+		newOwned("apps/v1", "ReplicaSet", "web-abc", "Deployment", "web"),
+		newOwned("v1", "Pod", "web-abc-1", "ReplicaSet", "web-abc"),
+		newOwned("v1", "Pod", "web-abc-2", "ReplicaSet", "web-abc"),
+		newOwned("batch/v1", "CronJob", "backup", "", ""),
+		newOwned("batch/v1", "Job", "backup-123", "CronJob", "backup"),
+		newOwned("v1", "Pod", "backup-123-x", "Job", "backup-123"),
+		newOwned("v1", "Pod", "standalone", "", ""),
+	}
+}
+
+func TestExecuteOwnerFields(t *testing.T) {
+	e := newFakeExecutor(ownerFixtures()...)
+	query := mustParse(t, "name, owner.kind, owner.name, root_owner FROM pod WHERE namespace = default ORDER BY name")
+
+	results, _, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := map[string][3]interface{}{
+		"backup-123-x": {"Job", "backup-123", "CronJob/backup"},
+		"standalone":   {nil, nil, nil},
+		"web-abc-1":    {"ReplicaSet", "web-abc", "Deployment/web"},
+		"web-abc-2":    {"ReplicaSet", "web-abc", "Deployment/web"},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d rows, got %d", len(expected), len(results))
+	}
+	for _, row := range results {
+		want := expected[row["name"].(string)]
+		got := [3]interface{}{row["owner.kind"], row["owner.name"], row["root_owner"]}
+		if got != want {
+			t.Errorf("%s: expected %v, got %v", row["name"], want, got)
+		}
+	}
+}
+
+func TestExecuteGroupByRootOwner(t *testing.T) {
+	e := newFakeExecutor(ownerFixtures()...)
+	query := mustParse(t, "root_owner.kind, COUNT as pods FROM pod WHERE namespace = default AND owner.kind != ReplicaSet GROUP BY root_owner.kind")
+
+	results, _, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	counts := map[interface{}]interface{}{}
+	for _, row := range results {
+		counts[row["root_owner.kind"]] = row["pods"]
+	}
+	if counts["CronJob"] != 1 || counts[nil] != 1 || len(counts) != 2 {
+		t.Errorf("Unexpected groups: %v", counts)
+	}
+}
+
+func TestExecuteDescendants(t *testing.T) {
+	e := newFakeExecutor(ownerFixtures()...)
+	query := mustParse(t, "kind, name, depth, owner.name FROM DESCENDANTS OF deployment/web WHERE namespace = default")
+
+	results, _, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected ReplicaSet and 2 Pods, got %d: %v", len(results), results)
+	}
+	if results[0]["kind"] != "ReplicaSet" || results[0]["depth"] != 1 {
+		t.Errorf("Expected ReplicaSet at depth 1 first, got %v", results[0])
+	}
+	for _, row := range results[1:] {
+		if row["kind"] != "Pod" || row["depth"] != 2 || row["owner.name"] != "web-abc" {
+			t.Errorf("Expected pod owned by web-abc at depth 2, got %v", row)
+		}
+	}
+
+	query = mustParse(t, "name FROM DESCENDANTS OF deployment/missing WHERE namespace = default")
+	if _, _, err := e.Execute(query); err == nil {
+		t.Error("Expected error for missing root object")
+	}
+}
+
+func TestExecuteDescendantsAllNamespaces(t *testing.T) {
+	objects := ownerFixtures()
+	for _, obj := range ownerFixtures()[:3] {
+		obj := obj.(*unstructured.Unstructured)
+		obj.SetNamespace("prod")
+		obj.SetUID("prod-" + obj.GetUID())
+		if refs := obj.GetOwnerReferences(); len(refs) > 0 {
+			refs[0].UID = "prod-" + refs[0].UID
+			obj.SetOwnerReferences(refs)
+		}
+		objects = append(objects, obj)
+	}
+	e := newFakeExecutor(objects...)
+	e.CurrentNamespace = ""
+
+	results, _, err := e.Execute(mustParse(t, "namespace, kind, name, depth FROM DESCENDANTS OF deployment/web"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	expected := []string{"default/web-abc", "default/web-abc-1", "default/web-abc-2", "prod/web-abc", "prod/web-abc-1"}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, results)
+	}
+	for i, row := range results {
+		if got := row["namespace"].(string) + "/" + row["name"].(string); got != expected[i] {
+			t.Errorf("Row %d: expected %s, got %s", i, expected[i], got)
+		}
+	}
+
+	query := mustParse(t, "name FROM DESCENDANTS OF deployment/missing")
+	if _, _, err := e.Execute(query); err == nil {
+		t.Error("Expected error for missing root object")
+	}
+}
@@ -0,0 +1,227 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// sourcedRule is a PolicyRule along with the ClusterRole it was aggregated
+// from; from is empty for the role's own rules.
+type sourcedRule struct {
+	rule map[string]interface{}
+	from string
+}
+
+// scanRBAC expands RoleBindings in scope and all ClusterRoleBindings into one
+// row per permission they grant. Bindings to missing roles grant nothing.
+func (e *Executor) scanRBAC(ctx context.Context, resDef *registry.ResourceDefinition, scope *parser.Query, refs []string) ([]map[string]interface{}, error) {
+	list := func(name, namespace string) ([]unstructured.Unstructured, error) {
+		def, ok := e.registry.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown resource: %s", name)
+		}
+		return e.fetchResources(ctx, def, &parser.Query{Namespace: namespace})
+	}
+	roleBindings, err := list("rolebinding", scope.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := list("role", scope.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	clusterRoleBindings, err := list("clusterrolebinding", "")
+	if err != nil {
+		return nil, err
+	}
+	clusterRoles, err := list("clusterrole", "")
+	if err != nil {
+		return nil, err
+	}
+
+	roleRules := make(map[string][]sourcedRule, len(roles))
+	for _, role := range roles {
+		roleRules[role.GetNamespace()+"/"+role.GetName()] = ownRules(role)
+	}
+	clusterRoleRules, err := aggregateClusterRoles(clusterRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicMapFields := collectDynamicMapFields(refs, resDef)
+	rawPaths := collectRawPaths(refs, resDef)
+	var rows []map[string]interface{}
+	for _, binding := range append(roleBindings, clusterRoleBindings...) {
+		bindingKind := "RoleBinding"
+		namespace := binding.GetNamespace()
+		roleKind, _, _ := unstructured.NestedString(binding.Object, "roleRef", "kind")
+		roleName, _, _ := unstructured.NestedString(binding.Object, "roleRef", "name")
+		rules := clusterRoleRules[roleName]
+		if roleKind == "Role" {
+			rules = roleRules[namespace+"/"+roleName]
+		}
+		if namespace == "" {
+			bindingKind, namespace = "ClusterRoleBinding", "*"
+		}
+
+		subjects, _, _ := unstructured.NestedSlice(binding.Object, "subjects")
+		for _, s := range subjects {
+			subject, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			seen := make(map[string]bool)
+			for _, perm := range expandRules(rules) {
+				key := strings.Join([]string{perm.verb, perm.apiGroup, perm.resource, perm.resourceName, perm.url}, "\x00")
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				obj := map[string]interface{}{
+					"metadata": map[string]interface{}{"namespace": namespace},
+					"subject":  subject,
+					"verb":     perm.verb,
+					"binding":  bindingKind + "/" + binding.GetName(),
+					"role":     roleKind + "/" + roleName,
+				}
+				if perm.url != "" {
+					obj["nonResourceURL"] = perm.url
+				} else {
+					obj["apiGroup"], obj["resource"] = perm.apiGroup, perm.resource
+				}
+				if perm.resourceName != "" {
+					obj["resourceName"] = perm.resourceName
+				}
+				if perm.from != "" {
+					obj["aggregatedFrom"] = perm.from
+				}
+				rows = append(rows, e.buildRow(&unstructured.Unstructured{Object: obj}, resDef, dynamicMapFields, rawPaths))
+			}
+		}
+	}
+	return rows, nil
+}
+
+// aggregateClusterRoles returns the rules of each ClusterRole by name. The
+// rules of a role with an aggregationRule are those of every ClusterRole its
+// selectors match, transitively.
+func aggregateClusterRoles(clusterRoles []unstructured.Unstructured) (map[string][]sourcedRule, error) {
+	sort.Slice(clusterRoles, func(i, j int) bool { return clusterRoles[i].GetName() < clusterRoles[j].GetName() })
+	selectors := make(map[string][]labels.Selector)
+	for _, role := range clusterRoles {
+		terms, _, _ := unstructured.NestedSlice(role.Object, "aggregationRule", "clusterRoleSelectors")
+		for _, term := range terms {
+			m, ok := term.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var ls metav1.LabelSelector
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &ls); err != nil {
+				return nil, fmt.Errorf("invalid aggregationRule in clusterrole %s: %w", role.GetName(), err)
+			}
+			selector, err := metav1.LabelSelectorAsSelector(&ls)
+			if err != nil {
+				return nil, fmt.Errorf("invalid aggregationRule in clusterrole %s: %w", role.GetName(), err)
+			}
+			selectors[role.GetName()] = append(selectors[role.GetName()], selector)
+		}
+	}
+
+	// The rules of an aggregated role are managed by the aggregation
+	// controller, so they are rebuilt from the selected roles instead
+	var collect func(name string, visited map[string]bool) []sourcedRule
+	collect = func(name string, visited map[string]bool) []sourcedRule {
+		visited[name] = true
+		var rules []sourcedRule
+		for _, role := range clusterRoles {
+			if role.GetName() != name {
+				continue
+			}
+			if _, aggregated := selectors[name]; !aggregated {
+				return ownRules(role)
+			}
+			for _, source := range clusterRoles {
+				if visited[source.GetName()] || !matchesAny(selectors[name], source.GetLabels()) {
+					continue
+				}
+				for _, r := range collect(source.GetName(), visited) {
+					if r.from == "" {
+						r.from = source.GetName()
+					}
+					rules = append(rules, r)
+				}
+			}
+		}
+		return rules
+	}
+
+	result := make(map[string][]sourcedRule, len(clusterRoles))
+	for _, role := range clusterRoles {
+		result[role.GetName()] = collect(role.GetName(), make(map[string]bool))
+	}
+	return result, nil
+}
+
+func matchesAny(selectors []labels.Selector, set map[string]string) bool {
+	for _, selector := range selectors {
+		if !selector.Empty() && selector.Matches(labels.Set(set)) {
+			return true
+		}
+	}
+	return false
+}
+
+func ownRules(role unstructured.Unstructured) []sourcedRule {
+	list, _, _ := unstructured.NestedSlice(role.Object, "rules")
+	var rules []sourcedRule
+	for _, r := range list {
+		if rule, ok := r.(map[string]interface{}); ok {
+			rules = append(rules, sourcedRule{rule: rule})
+		}
+	}
+	return rules
+}
+
+// permission is one verb on one resource (or non-resource URL) of a rule.
+type permission struct {
+	verb, apiGroup, resource, resourceName, url, from string
+}
+
+// expandRules flattens rules into permissions, one per verb, API group,
+// resource and resource name, or per verb and non-resource URL.
+func expandRules(rules []sourcedRule) []permission {
+	var perms []permission
+	for _, r := range rules {
+		verbs, _, _ := unstructured.NestedStringSlice(r.rule, "verbs")
+		groups, _, _ := unstructured.NestedStringSlice(r.rule, "apiGroups")
+		resources, _, _ := unstructured.NestedStringSlice(r.rule, "resources")
+		names, _, _ := unstructured.NestedStringSlice(r.rule, "resourceNames")
+		urls, _, _ := unstructured.NestedStringSlice(r.rule, "nonResourceURLs")
+		if len(names) == 0 {
+			names = []string{""}
+		}
+		for _, verb := range verbs {
+			for _, group := range groups {
+				for _, resource := range resources {
+					for _, name := range names {
+						perms = append(perms, permission{verb: verb, apiGroup: group, resource: resource, resourceName: name, from: r.from})
+					}
+				}
+			}
+			for _, url := range urls {
+				perms = append(perms, permission{verb: verb, url: url, from: r.from})
+			}
+		}
+	}
+	return perms
+}
@@ -0,0 +1,137 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func rbacObject(kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	metadata := map[string]interface{}{"name": name}
+	if namespace != "" {
+		metadata["namespace"] = namespace
+	}
+	if labels, ok := fields["labels"]; ok {
+		metadata["labels"] = labels
+		delete(fields, "labels")
+	}
+	obj := map[string]interface{}{"apiVersion": "rbac.authorization.k8s.io/v1", "kind": kind, "metadata": metadata}
+	for k, v := range fields {
+		obj[k] = v
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func rule(verbs, resources []interface{}, extra ...string) map[string]interface{} {
+	r := map[string]interface{}{"apiGroups": []interface{}{""}, "verbs": verbs, "resources": resources}
+	for i := 0; i+1 < len(extra); i += 2 {
+		r[extra[i]] = []interface{}{extra[i+1]}
+	}
+	return r
+}
+
+func newRBACExecutor() *Executor {
+	group := "rbac.authorization.k8s.io"
+	binding := func(kind, namespace, name, roleKind, role string, subjects ...interface{}) *unstructured.Unstructured {
+		return rbacObject(kind, namespace, name, map[string]interface{}{
+			"roleRef":  map[string]interface{}{"apiGroup": group, "kind": roleKind, "name": role},
+			"subjects": subjects,
+		})
+	}
+	subject := func(kind, name string) map[string]interface{} {
+		return map[string]interface{}{"kind": kind, "name": name}
+	}
+	objects := []runtime.Object{
+		rbacObject("Role", "prod", "secret-reader", map[string]interface{}{
+			"rules": []interface{}{rule([]interface{}{"get", "list"}, []interface{}{"secrets"})},
+		}),
+		binding("RoleBinding", "prod", "read-secrets", "Role", "secret-reader",
+			subject("User", "alice"), map[string]interface{}{"kind": "ServiceAccount", "name": "ci", "namespace": "prod"}),
+		binding("RoleBinding", "prod", "dangling", "Role", "missing", subject("User", "carol")),
+
+		rbacObject("ClusterRole", "", "cleanup", map[string]interface{}{
+			"rules": []interface{}{
+				rule([]interface{}{"delete"}, []interface{}{"secrets"}, "resourceNames", "old-token"),
+				map[string]interface{}{"verbs": []interface{}{"get"}, "nonResourceURLs": []interface{}{"/healthz"}},
+			},
+		}),
+		binding("ClusterRoleBinding", "", "cleanup", "ClusterRole", "cleanup", subject("Group", "ops")),
+
+		// admin-agg still holds a stale copy of its aggregated rules
+		rbacObject("ClusterRole", "", "pod-deleter", map[string]interface{}{
+			"labels": map[string]interface{}{"agg": "true"},
+			"rules":  []interface{}{rule([]interface{}{"delete"}, []interface{}{"pods"})},
+		}),
+		rbacObject("ClusterRole", "", "admin-agg", map[string]interface{}{
+			"aggregationRule": map[string]interface{}{"clusterRoleSelectors": []interface{}{
+				map[string]interface{}{"matchLabels": map[string]interface{}{"agg": "true"}},
+			}},
+			"rules": []interface{}{rule([]interface{}{"delete"}, []interface{}{"pods"}), rule([]interface{}{"*"}, []interface{}{"*"})},
+		}),
+		binding("RoleBinding", "prod", "team-admin", "ClusterRole", "admin-agg", subject("User", "bob")),
+	}
+	return &Executor{
+		source:           fakeSource(objects...),
+		registry:         registry.GetGlobalRegistry(),
+		CurrentNamespace: "default",
+	}
+}
+
+func TestExecuteRBAC(t *testing.T) {
+	e := newRBACExecutor()
+
+	results, _, err := e.Execute(mustParse(t, "subject_kind, subject, namespace, resourceName, via_binding, via_role FROM rbac WHERE verb = 'delete' AND resource = 'secrets'"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected one subject able to delete secrets, got %v", results)
+	}
+	ops := results[0]
+	if ops["subject_kind"] != "Group" || ops["subject"] != "ops" || ops["namespace"] != "*" || ops["resourceName"] != "old-token" ||
+		ops["via_binding"] != "ClusterRoleBinding/cleanup" || ops["via_role"] != "ClusterRole/cleanup" {
+		t.Errorf("Unexpected row: %v", ops)
+	}
+
+	results, _, err = e.Execute(mustParse(t, "subject, subject_namespace, verb FROM rbac WHERE namespace = prod AND resource = secrets ORDER BY subject, verb"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 4 || results[0]["subject"] != "alice" || results[0]["verb"] != "get" || results[2]["subject"] != "ci" || results[2]["subject_namespace"] != "prod" {
+		t.Errorf("Expected get and list for alice and ci, got %v", results)
+	}
+
+	results, _, err = e.Execute(mustParse(t, "verb, nonResourceURL FROM rbac WHERE nonResourceURL = /healthz"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 1 || results[0]["verb"] != "get" || results[0]["resource"] != nil {
+		t.Errorf("Expected one non-resource permission, got %v", results)
+	}
+}
+
+func TestExecuteRBACAggregation(t *testing.T) {
+	e := newRBACExecutor()
+	results, _, err := e.Execute(mustParse(t, "subject, verb, resource, via_role, aggregated_from FROM rbac WHERE namespace = prod AND subject = bob"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	// The stale rules of admin-agg are replaced by those it selects
+	if len(results) != 1 {
+		t.Fatalf("Expected the single aggregated rule, got %v", results)
+	}
+	if results[0]["verb"] != "delete" || results[0]["resource"] != "pods" || results[0]["via_role"] != "ClusterRole/admin-agg" || results[0]["aggregated_from"] != "pod-deleter" {
+		t.Errorf("Unexpected aggregated row: %v", results[0])
+	}
+
+	results, _, err = e.Execute(mustParse(t, "subject FROM rbac WHERE subject = carol"))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected a binding to a missing role to grant nothing, got %v", results)
+	}
+}
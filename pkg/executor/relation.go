@@ -0,0 +1,196 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+)
+
+// relation is a query result held in memory so it can appear in FROM and
+// JOIN like a registry resource. Rows hold only the output columns.
+type relation struct {
+	def  *registry.ResourceDefinition
+	rows []map[string]interface{}
+}
+
+func newRelation(name string, rows []map[string]interface{}, columns []string) *relation {
+	return &relation{
+		def:  registry.NewRelationDefinition(name, columns),
+		rows: projectRows(rows, columns),
+	}
+}
+
+// scan returns a copy of the relation rows so clauses applied by the reading
+// query cannot modify the relation.
+func (r *relation) scan() []map[string]interface{} {
+	rows := make([]map[string]interface{}, len(r.rows))
+	for i, row := range r.rows {
+		rows[i] = copyRow(row)
+	}
+	return rows
+}
+
+// projectRows returns copies of rows holding only columns.
+func projectRows(rows []map[string]interface{}, columns []string) []map[string]interface{} {
+	projected := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		p := make(map[string]interface{}, len(columns))
+		for _, col := range columns {
+			p[col] = row[col]
+		}
+		projected[i] = p
+	}
+	return projected
+}
+
+// lookupResource resolves a FROM or JOIN name to a WITH relation in scope, a
+// registry resource or a resource found through API discovery. rel is nil
+// unless name is a WITH relation.
+func (e *Executor) lookupResource(ctx context.Context, name string) (def *registry.ResourceDefinition, rel *relation, ok bool) {
+	if rel, ok := e.relations[name]; ok {
+		return rel.def, rel, true
+	}
+	if def, ok = e.registry.Get(name); ok {
+		return def, nil, true
+	}
+	def, ok = e.discoverResource(ctx, name)
+	return def, nil, ok
+}
+
+// bindCTEs executes the WITH queries of query in order and brings their
+// results into scope, each one visible to the queries after it. CTE names
+// shadow registry resources of the same name. The returned func restores
+// the previous scope.
+func (e *Executor) bindCTEs(ctx context.Context, query *parser.Query) (func(), error) {
+	prevRegistry, prevRelations := e.registry, e.relations
+	restore := func() {
+		e.registry, e.relations = prevRegistry, prevRelations
+	}
+
+	e.registry = prevRegistry.Clone()
+	e.relations = make(map[string]*relation, len(prevRelations)+len(query.With))
+	for name, rel := range prevRelations {
+		e.relations[name] = rel
+	}
+
+	for _, cte := range query.With {
+		// Inherit namespace from outer query if not specified
+		if cte.Query.Namespace == "" {
+			cte.Query.Namespace = query.Namespace
+		}
+		step := addStep(ctx, "With", cte.Name, "evaluated before the main query")
+		rows, fields, err := e.execute(step.enter(ctx), cte.Query)
+		if err != nil {
+			restore()
+			return nil, fmt.Errorf("WITH %s: %w", cte.Name, err)
+		}
+		step.done(len(rows))
+		rel := newRelation(cte.Name, rows, fields)
+		e.relations[cte.Name] = rel
+		e.registry.Register(rel.def)
+	}
+	return restore, nil
+}
+
+// executeSetOperations runs the head query and each UNION / INTERSECT /
+// EXCEPT operand, combining them left to right. Operands must return the
+// same number of columns; their rows are matched to the head's column names
+// by position.
+func (e *Executor) executeSetOperations(ctx context.Context, query *parser.Query) ([]map[string]interface{}, []string, error) {
+	head := *query
+	head.With = nil
+	head.SetOps = nil
+	step := addStep(ctx, "Query", head.Resource, "first operand")
+	rows, fields, err := e.execute(step.enter(ctx), &head)
+	if err != nil {
+		return nil, nil, err
+	}
+	step.done(len(rows))
+	results := projectRows(rows, fields)
+
+	for _, op := range query.SetOps {
+		// Inherit namespace from the head query if not specified
+		if op.Query.Namespace == "" {
+			op.Query.Namespace = query.Namespace
+		}
+		operator := string(op.Operator)
+		if op.All {
+			operator += " ALL"
+		}
+		step := addStep(ctx, operator, op.Query.Resource, "columns matched by position")
+		opRows, opFields, err := e.execute(step.enter(ctx), op.Query)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op.Operator, err)
+		}
+		if len(opFields) != len(fields) {
+			return nil, nil, fmt.Errorf("%s operands must return the same number of columns (%d vs %d)", op.Operator, len(fields), len(opFields))
+		}
+
+		// Rename operand columns to the head's column names
+		right := make([]map[string]interface{}, len(opRows))
+		for i, row := range opRows {
+			renamed := make(map[string]interface{}, len(fields))
+			for j, f := range fields {
+				renamed[f] = row[opFields[j]]
+			}
+			right[i] = renamed
+		}
+
+		results = combineRows(results, right, fields, op)
+		step.done(len(results))
+	}
+	return results, fields, nil
+}
+
+// combineRows applies one set operation. Without ALL the result holds each
+// distinct row once; with ALL duplicates are kept (UNION ALL) or matched one
+// for one (INTERSECT ALL, EXCEPT ALL).
+func combineRows(left, right []map[string]interface{}, fields []string, op parser.SetOperation) []map[string]interface{} {
+	if op.Operator == parser.Union {
+		combined := append(left, right...)
+		if op.All {
+			return combined
+		}
+		return applyDistinct(combined, fields)
+	}
+
+	// Count right rows by key
+	counts := make(map[string]int, len(right))
+	for _, row := range right {
+		counts[rowKey(row, fields)]++
+	}
+
+	var results []map[string]interface{}
+	emitted := make(map[string]bool)
+	for _, row := range left {
+		key := rowKey(row, fields)
+		matched := counts[key] > 0
+		if op.All && matched {
+			counts[key]--
+		}
+		keep := matched == (op.Operator == parser.Intersect)
+		if !keep {
+			continue
+		}
+		if !op.All {
+			if emitted[key] {
+				continue
+			}
+			emitted[key] = true
+		}
+		results = append(results, row)
+	}
+	return results
+}
+
+// rowKey identifies a row by its values for fields.
+func rowKey(row map[string]interface{}, fields []string) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = fmt.Sprintf("%v", row[f])
+	}
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestExecuteCTEJoin(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+	query := mustParse(t, "WITH pending AS (SELECT name, labels.app FROM pod WHERE namespace = default AND status = Pending) SELECT p.name, svc.name FROM pending p JOIN service svc ON p.labels.app = svc.selector.app WHERE svc.namespace = default")
+
+	results, fields, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 row, got %d: %v", len(results), results)
+	}
+	if results[0]["p.name"] != "api-1" || results[0]["svc.name"] != "api-svc" {
+		t.Errorf("Expected api-1 joined to api-svc, got %v", results[0])
+	}
+	if len(fields) != 2 || fields[0] != "p.name" || fields[1] != "svc.name" {
+		t.Errorf("Expected fields [p.name svc.name], got %v", fields)
+	}
+
+	// The CTE is only in scope for its own query
+	if _, _, err := e.Execute(mustParse(t, "name FROM pending")); err == nil {
+		t.Error("Expected unknown resource error after the WITH query finished")
+	}
+}
+
+func TestExecuteCTEDefaultColumns(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+	query := mustParse(t, "WITH apps AS (SELECT labels.app, COUNT as pods FROM pod WHERE namespace = default GROUP BY labels.app) SELECT * FROM apps WHERE pods > 1")
+
+	results, fields, err := e.Execute(query)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(fields) != 2 || fields[0] != "labels.app" || fields[1] != "pods" {
+		t.Errorf("Expected CTE columns [labels.app pods], got %v", fields)
+	}
+	if len(results) != 1 || results[0]["labels.app"] != "web" {
+		t.Errorf("Expected only the web group, got %v", results)
+	}
+}
+
+func TestExecuteSetOperations(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "union removes duplicates",
+			sql:  "labels.app FROM pod WHERE namespace = default UNION selector.app FROM service WHERE namespace = default",
+			want: []string{"api", "web"},
+		},
+		{
+			name: "union all keeps duplicates",
+			sql:  "labels.app FROM pod WHERE namespace = default UNION ALL selector.app FROM service WHERE namespace = default",
+			want: []string{"api", "api", "web", "web", "web"},
+		},
+		{
+			name: "intersect",
+			sql:  "labels.app FROM pod WHERE status = Pending INTERSECT selector.app FROM service",
+			want: []string{"api"},
+		},
+		{
+			name: "except",
+			sql:  "labels.app FROM pod WHERE namespace = default EXCEPT selector.app FROM service WHERE namespace = default AND name = api-svc",
+			want: []string{"web"},
+		},
+		{
+			name: "except all",
+			sql:  "labels.app FROM pod WHERE namespace = default EXCEPT ALL selector.app FROM service WHERE namespace = default",
+			want: []string{"web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newFakeExecutor(joinFixtures()...)
+			results, fields, err := e.Execute(mustParse(t, tt.sql))
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if len(fields) != 1 || fields[0] != "labels.app" {
+				t.Errorf("Expected head column labels.app, got %v", fields)
+			}
+			var got []string
+			for _, row := range results {
+				got = append(got, row["labels.app"].(string))
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i, want := range tt.want {
+				if got[i] != want {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteSetOperationColumnMismatch(t *testing.T) {
+	e := newFakeExecutor(joinFixtures()...)
+	_, _, err := e.Execute(mustParse(t, "name FROM pod UNION name, type FROM service"))
+	if err == nil {
+		t.Fatal("Expected error for operands with different column counts")
+	}
+}
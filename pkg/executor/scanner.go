@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// scanner produces the rows of the resources of one registry.Scanner kind.
+type scanner struct {
+	// scan returns the unfiltered rows of resDef in scope.
+	scan func(e *Executor, ctx context.Context, resDef *registry.ResourceDefinition, scope *parser.Query, refs []string) ([]map[string]interface{}, error)
+	// target is what EXPLAIN says the scan reads; nil means the listed resource.
+	target func(scope *parser.Query) string
+	// details are added to what EXPLAIN says about the scan.
+	details []string
+	// captures are the resources snapshot save captures so the resource can
+	// be queried offline; nil means the resource itself.
+	captures []string
+}
+
+// scannerFor returns the scanner of resDef. A new virtual resource needs
+// only its registry.Scanner and a case here.
+func scannerFor(resDef *registry.ResourceDefinition) scanner {
+	switch resDef.Scanner {
+	case registry.ScanObjects:
+		return scanner{scan: objectScan(nil)}
+	case registry.ScanContainers:
+		return scanner{scan: objectScan(func(_ *Executor, _ context.Context, pods []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+			return containerItems(pods), nil
+		})}
+	case registry.ScanLogs:
+		return scanner{
+			scan: (*Executor).scanLogs,
+			target: func(scope *parser.Query) string {
+				if scope.LogsOf != nil {
+					return "logs(" + scope.LogsOf.Resource + " query)"
+				}
+				return "logs of pods"
+			},
+			details: []string{"WHERE and LIMIT applied while reading"},
+		}
+	case registry.ScanRBAC:
+		return scanner{
+			scan:     (*Executor).scanRBAC,
+			target:   func(*parser.Query) string { return "rbac bindings and roles" },
+			captures: []string{"rolebinding", "role", "clusterrolebinding", "clusterrole"},
+		}
+	case registry.ScanAllocation:
+		// Sum the requests and limits of the pods on each node
+		return scanner{
+			scan: objectScan(func(e *Executor, ctx context.Context, nodes []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+				return nodes, e.attachAllocation(ctx, nodes)
+			}),
+			details:  []string{"requests of running pods"},
+			captures: []string{"pod", "node"},
+		}
+	}
+	return scanner{scan: func(*Executor, context.Context, *registry.ResourceDefinition, *parser.Query, []string) ([]map[string]interface{}, error) {
+		return nil, fmt.Errorf("resource %s has unknown scanner %q", resDef.Name, resDef.Scanner)
+	}}
+}
+
+// objectScan returns a scan of the listed objects of a resource, which
+// prepare, if not nil, may change or replace before rows are built.
+func objectScan(prepare func(e *Executor, ctx context.Context, items []unstructured.Unstructured) ([]unstructured.Unstructured, error)) func(*Executor, context.Context, *registry.ResourceDefinition, *parser.Query, []string) ([]map[string]interface{}, error) {
+	return func(e *Executor, ctx context.Context, resDef *registry.ResourceDefinition, scope *parser.Query, refs []string) ([]map[string]interface{}, error) {
+		return e.scanObjects(ctx, resDef, scope, refs, prepare)
+	}
+}
@@ -0,0 +1,198 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bangmodtechnology/kselect/pkg/output"
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Capture lists the objects behind resources in namespace ("" or "*" for
+// all), e.g. to save a snapshot that queries against those resources can
+// run on later. Derived resources bring the objects their rows are built
+// from: pods for container and logs, bindings and roles for rbac, nodes and
+// pods for node_allocation. Logs and metrics are not objects and are not
+// captured, nor are managedFields. Timeout bounds the capture as a whole.
+func (e *Executor) Capture(ctx context.Context, resources []string, namespace string) ([]unstructured.Unstructured, error) {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	seen := make(map[schema.GroupVersionResource]bool)
+	var objects []unstructured.Unstructured
+	var capture func(name string) error
+	capture = func(name string) error {
+		resDef, ok := e.registry.Get(name)
+		if !ok {
+			if resDef, ok = e.discoverResource(ctx, name); !ok {
+				return fmt.Errorf("unknown resource: %s (use --list to see available resources)", name)
+			}
+		}
+		if captures := scannerFor(resDef).captures; captures != nil {
+			for _, dep := range captures {
+				if err := capture(dep); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if seen[resDef.GroupVersionResource] {
+			return nil
+		}
+		seen[resDef.GroupVersionResource] = true
+
+		items, err := e.fetchResources(ctx, resDef, &parser.Query{Namespace: namespace})
+		if err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].SetManagedFields(nil)
+		}
+		objects = append(objects, items...)
+		return nil
+	}
+
+	for _, name := range resources {
+		if err := capture(name); err != nil {
+			return nil, e.timeoutError(ctx, err)
+		}
+	}
+	return objects, nil
+}
+
+// Diff runs query against before and after, e.g. executors over snapshots
+// taken at two points in time, and returns the rows that were added,
+// removed or changed, led by a change column. Rows are matched by namespace
+// and name (and pod, for containers), or by the GROUP BY fields of grouped
+// queries; key fields the query leaves out are added. Rows that share a key
+// are an error, since they cannot be matched.
+func Diff(ctx context.Context, before, after *Executor, query *parser.Query) ([]map[string]interface{}, []string, error) {
+	if len(query.Joins) > 0 || len(query.With) > 0 || len(query.SetOps) > 0 {
+		return nil, nil, fmt.Errorf("diff supports queries over a single resource, without JOIN, WITH or set operations")
+	}
+
+	keys := query.GroupBy
+	if len(keys) == 0 && len(query.Aggregates) == 0 {
+		resDef, _, ok := before.lookupResource(ctx, query.Resource)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown resource: %s (use --list to see available resources)", query.Resource)
+		}
+		resolveQueryAliases(query, resDef.ResolveFieldAlias)
+		fields := before.resolveFields(query, resDef)
+		if !slices.Contains(fields, "name") {
+			return nil, nil, fmt.Errorf("diff matches rows by name: add name to the fields")
+		}
+		keys = []string{"name"}
+		// Container names repeat across pods
+		if resDef.Scanner == registry.ScanContainers {
+			keys = []string{"pod", "name"}
+		}
+		if resDef.Namespaced {
+			keys = append([]string{"namespace"}, keys...)
+		}
+		var missing []string
+		for _, k := range keys {
+			if !slices.Contains(fields, k) {
+				missing = append(missing, k)
+			}
+		}
+		if len(missing) > 0 {
+			query.Fields = append(missing, fields...)
+			query.UseDefault = false
+		}
+	}
+
+	beforeRows, fields, err := before.ExecuteContext(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("before: %w", err)
+	}
+	afterRows, _, err := after.ExecuteContext(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("after: %w", err)
+	}
+	for _, rows := range [][]map[string]interface{}{beforeRows, afterRows} {
+		seen := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			key := diffKey(row, keys)
+			if seen[key] {
+				return nil, nil, fmt.Errorf("rows are not unique by %s; GROUP BY a key", strings.Join(keys, "/"))
+			}
+			seen[key] = true
+		}
+	}
+	return DiffResults(beforeRows, afterRows, fields, keys), append([]string{"change"}, fields...), nil
+}
+
+// DiffResults compares rows matched by the values of keys, as applyDistinct
+// matches rows by their fields, and returns those only in after ("added"),
+// only in before ("removed"), or in both with different fields ("changed"),
+// ordered by key. Changed fields read "old → new".
+func DiffResults(before, after []map[string]interface{}, fields, keys []string) []map[string]interface{} {
+	beforeByKey := make(map[string]map[string]interface{}, len(before))
+	for _, row := range before {
+		beforeByKey[diffKey(row, keys)] = row
+	}
+	afterByKey := make(map[string]map[string]interface{}, len(after))
+	for _, row := range after {
+		afterByKey[diffKey(row, keys)] = row
+	}
+
+	type keyedRow struct {
+		key string
+		row map[string]interface{}
+	}
+	var diff []keyedRow
+	withChange := func(row map[string]interface{}, change string) map[string]interface{} {
+		out := make(map[string]interface{}, len(row)+1)
+		for k, v := range row {
+			out[k] = v
+		}
+		out["change"] = change
+		return out
+	}
+	for key, row := range afterByKey {
+		old, ok := beforeByKey[key]
+		if !ok {
+			diff = append(diff, keyedRow{key, withChange(row, "added")})
+			continue
+		}
+		changed := withChange(row, "changed")
+		differs := false
+		for _, f := range fields {
+			if fmt.Sprintf("%v", old[f]) != fmt.Sprintf("%v", row[f]) {
+				changed[f] = output.FormatValue(old[f]) + " → " + output.FormatValue(row[f])
+				differs = true
+			}
+		}
+		if differs {
+			diff = append(diff, keyedRow{key, changed})
+		}
+	}
+	for key, row := range beforeByKey {
+		if _, ok := afterByKey[key]; !ok {
+			diff = append(diff, keyedRow{key, withChange(row, "removed")})
+		}
+	}
+
+	sort.Slice(diff, func(i, j int) bool { return diff[i].key < diff[j].key })
+	results := make([]map[string]interface{}, len(diff))
+	for i, d := range diff {
+		results[i] = d.row
+	}
+	return results
+}
+
+// diffKey returns the values of keys in row, joined.
+func diffKey(row map[string]interface{}, keys []string) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%v", row[k])
+	}
+	return strings.Join(parts, "|")
+}
@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bangmodtechnology/kselect/pkg/registry"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCapture(t *testing.T) {
+	pod := newPod("prod", "web-1", "web", "Running", 0, "")
+	pod.Object["metadata"].(map[string]interface{})["managedFields"] = []interface{}{map[string]interface{}{"manager": "kubectl"}}
+	e := New(fakeSource(
+		pod,
+		newPod("dev", "api-1", "api", "Running", 0, ""),
+		newService("prod", "web", "web"),
+		rbacObject("ClusterRole", "", "view", nil),
+	))
+
+	objects, err := e.Capture(context.Background(), []string{"pod", "container", "svc", "rbac"}, "prod")
+	if err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+	var got []string
+	for _, obj := range objects {
+		got = append(got, obj.GetKind()+"/"+obj.GetName())
+		if obj.GetManagedFields() != nil {
+			t.Errorf("%s kept its managedFields", obj.GetName())
+		}
+	}
+	// Pods are captured once, and rbac brings the roles and bindings
+	if want := "Pod/web-1,Service/web,ClusterRole/view"; strings.Join(got, ",") != want {
+		t.Errorf("Captured %v, want %s", got, want)
+	}
+
+	if _, err := e.Capture(context.Background(), []string{"nosuch"}, ""); err == nil {
+		t.Error("Capture of an unknown resource succeeded")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := New(fakeSource(
+		newPod("prod", "web-1", "web", "Running", 0, "128Mi"),
+		newPod("prod", "web-2", "web", "Running", 1, "128Mi"),
+		newPod("dev", "web-1", "web", "Running", 0, "128Mi"),
+	))
+	after := New(fakeSource(
+		newPod("prod", "web-1", "web", "Running", 0, "128Mi"),
+		newPod("prod", "web-2", "web", "CrashLoopBackOff", 4, "128Mi"),
+		newPod("prod", "web-3", "web", "Pending", 0, "128Mi"),
+	))
+
+	// namespace is added, so web-1 in dev and prod are told apart
+	results, fields, err := Diff(context.Background(), before, after, mustParse(t, "name, status, restarts FROM pod"))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if strings.Join(fields, ",") != "change,namespace,name,status,restarts" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+	var got []string
+	for _, row := range results {
+		got = append(got, strings.Join([]string{row["change"].(string), row["namespace"].(string), row["name"].(string)}, " "))
+	}
+	if want := "removed dev web-1,changed prod web-2,added prod web-3"; strings.Join(got, ",") != want {
+		t.Fatalf("Diff = %v, want %s", got, want)
+	}
+	if changed := results[1]; changed["restarts"] != "1 → 4" || changed["name"] != "web-2" {
+		t.Errorf("Unexpected changed row %v", changed)
+	}
+
+	// Grouped queries are matched by their GROUP BY fields
+	results, _, err = Diff(context.Background(), before, after, mustParse(t, "namespace, COUNT as count FROM pod GROUP BY namespace"))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(results) != 2 || results[0]["change"] != "removed" || results[1]["change"] != "changed" || results[1]["count"] != "2 → 3" {
+		t.Errorf("Unexpected grouped diff %v", results)
+	}
+
+	if _, _, err := Diff(context.Background(), before, after, mustParse(t, "status FROM pod")); err == nil || !strings.Contains(err.Error(), "name") {
+		t.Errorf("Expected an error asking for name, got %v", err)
+	}
+}
+
+func TestDiffKeys(t *testing.T) {
+	// Containers are matched by pod as well as name
+	before := New(fakeSource(newPod("prod", "p1", "web", "Running", 0, "128Mi"), newPod("prod", "p2", "web", "Running", 0, "128Mi")))
+	after := New(fakeSource(newPod("prod", "p1", "web", "Running", 0, "256Mi"), newPod("prod", "p2", "web", "Running", 0, "512Mi")))
+	results, fields, err := Diff(context.Background(), before, after, mustParse(t, "name, mem.req-mi FROM container"))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if strings.Join(fields, ",") != "change,namespace,pod,name,mem.req-mi" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+	if len(results) != 2 || results[0]["pod"] != "p1" || results[1]["pod"] != "p2" {
+		t.Errorf("Expected the containers of both pods to change, got %v", results)
+	}
+
+	// Rows that share a key cannot be matched
+	reg := registry.NewRegistry()
+	reg.Register(&registry.ResourceDefinition{
+		Name:                 "app",
+		GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+		Namespaced:           true,
+		Fields: map[string]registry.FieldDefinition{
+			"namespace": {Name: "namespace", JSONPath: "{.metadata.namespace}", Type: "string"},
+			"name":      {Name: "name", JSONPath: "{.metadata.labels.app}", Type: "string"},
+		},
+	})
+	before, after = NewWithRegistry(before.source, reg), NewWithRegistry(after.source, reg)
+	_, _, err = Diff(context.Background(), before, after, mustParse(t, "name FROM app"))
+	if err == nil || !strings.Contains(err.Error(), "rows are not unique by namespace/name") {
+		t.Errorf("Expected an error for duplicate keys, got %v", err)
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	row := func(name string, value interface{}) map[string]interface{} {
+		return map[string]interface{}{"name": name, "value": value}
+	}
+	before := []map[string]interface{}{row("a", 1), row("b", nil)}
+	after := []map[string]interface{}{row("a", 1), row("b", "x")}
+	results := DiffResults(before, after, []string{"name", "value"}, []string{"name"})
+	if len(results) != 1 || results[0]["value"] != "<none> → x" {
+		t.Errorf("DiffResults = %v", results)
+	}
+	// Rows are copied, not changed in place
+	if after[1]["change"] != nil {
+		t.Error("DiffResults changed its input")
+	}
+}
@@ -1,45 +1,45 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/bangmodtechnology/kselect/pkg/output"
 	"github.com/bangmodtechnology/kselect/pkg/parser"
 )
 
-func (e *Executor) ExecuteWatch(query *parser.Query, interval time.Duration, format output.Format) error {
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
+// ExecuteWatch runs query every interval, redrawing the results, until ctx
+// is cancelled. Cancelling ctx also aborts a refresh in progress.
+func (e *Executor) ExecuteWatch(ctx context.Context, query *parser.Query, interval time.Duration, format output.Format) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	// Run immediately, then on interval
-	if err := e.runAndPrint(query, interval, format); err != nil {
+	if err := e.runAndPrint(ctx, query, interval, format); err != nil && ctx.Err() == nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 	}
 
 	for {
 		select {
 		case <-ticker.C:
-			if err := e.runAndPrint(query, interval, format); err != nil {
+			if err := e.runAndPrint(ctx, query, interval, format); err != nil && ctx.Err() == nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			}
-		case <-sigCh:
+		case <-ctx.Done():
 			fmt.Println("\nWatch stopped.")
 			return nil
 		}
 	}
 }
 
-func (e *Executor) runAndPrint(query *parser.Query, interval time.Duration, format output.Format) error {
+func (e *Executor) runAndPrint(ctx context.Context, query *parser.Query, interval time.Duration, format output.Format) error {
+	// Every refresh reads the cluster; the cache only serves repeated lists within it
+	e.Refresh()
 	start := time.Now()
-	results, fields, err := e.Execute(query)
+	results, fields, err := e.ExecuteContext(ctx, query)
 	elapsed := time.Since(start)
 	if err != nil {
 		return err
@@ -0,0 +1,622 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bangmodtechnology/kselect/pkg/parser"
+)
+
+type node interface {
+	eval(resolve Resolver) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n literalNode) eval(Resolver) (interface{}, error) {
+	return n.value, nil
+}
+
+type pathNode string
+
+func (n pathNode) eval(resolve Resolver) (interface{}, error) {
+	return normalize(resolve(string(n))), nil
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(resolve Resolver) (interface{}, error) {
+	v, err := n.operand.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !truthy(v), nil
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		return -v, nil
+	case float64:
+		return -v, nil
+	}
+	return nil, fmt.Errorf("cannot negate %s", describe(v))
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(resolve Resolver) (interface{}, error) {
+	left, err := n.left.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+	// && and || short-circuit
+	switch n.op {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(resolve)
+		return truthy(right), err
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(resolve)
+		return truthy(right), err
+	}
+
+	right, err := n.right.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compare(n.op, left, right)
+	}
+	return arithmetic(n.op, left, right)
+}
+
+type ternaryNode struct {
+	cond, then, otherwise node
+}
+
+func (n *ternaryNode) eval(resolve Resolver) (interface{}, error) {
+	cond, err := n.cond.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(cond) {
+		return n.then.eval(resolve)
+	}
+	return n.otherwise.eval(resolve)
+}
+
+type callNode struct {
+	name string
+	fn   function
+	args []node
+}
+
+func (n *callNode) eval(resolve Resolver) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(resolve)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := n.fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", n.name, err)
+	}
+	return v, nil
+}
+
+// normalize converts the numeric types found in objects to int64 or float64.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case []interface{}:
+		if v == nil {
+			return nil
+		}
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	}
+	return v
+}
+
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func equal(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	switch a.(type) {
+	case nil, bool, string:
+		return a == b
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func compare(op string, a, b interface{}) (interface{}, error) {
+	if a == nil || b == nil {
+		return false, nil
+	}
+	var cmp int
+	fa, aNum := toFloat(a)
+	fb, bNum := toFloat(b)
+	sa, aStr := a.(string)
+	sb, bStr := b.(string)
+	switch {
+	case aNum && bNum:
+		cmp = compareOrdered(fa, fb)
+	case aStr && bStr:
+		cmp = strings.Compare(sa, sb)
+	default:
+		return nil, fmt.Errorf("cannot compare %s with %s", describe(a), describe(b))
+	}
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+func compareOrdered(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// arithmetic applies + - * / %. A nil operand yields nil; + concatenates
+// when either side is a string.
+func arithmetic(op string, a, b interface{}) (interface{}, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	if op == "+" {
+		_, aStr := a.(string)
+		_, bStr := b.(string)
+		if aStr || bStr {
+			return toString(a) + toString(b), nil
+		}
+	}
+
+	ia, aInt := a.(int64)
+	ib, bInt := b.(int64)
+	if aInt && bInt {
+		switch op {
+		case "+":
+			return ia + ib, nil
+		case "-":
+			return ia - ib, nil
+		case "*":
+			return ia * ib, nil
+		case "/", "%":
+			if ib == 0 {
+				return nil, nil
+			}
+			if op == "%" {
+				return ia % ib, nil
+			}
+			if ia%ib == 0 {
+				return ia / ib, nil
+			}
+		}
+	}
+
+	fa, aNum := toFloat(a)
+	fb, bNum := toFloat(b)
+	if !aNum || !bNum {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, describe(a), describe(b))
+	}
+	switch op {
+	case "+":
+		return fa + fb, nil
+	case "-":
+		return fa - fb, nil
+	case "*":
+		return fa * fb, nil
+	case "/":
+		if fb == 0 {
+			return nil, nil
+		}
+		return fa / fb, nil
+	}
+	if fb == 0 {
+		return nil, nil
+	}
+	return math.Mod(fa, fb), nil
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func describe(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// toList returns v as a list; a single value is a one-element list, as a
+// [*] path with one match resolves to the bare value.
+func toList(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	}
+	return []interface{}{v}
+}
+
+// function is a built-in function. maxArgs -1 means variadic.
+type function struct {
+	minArgs, maxArgs int
+	call             func(args []interface{}) (interface{}, error)
+}
+
+func (f function) arity() string {
+	switch {
+	case f.minArgs == f.maxArgs:
+		return fmt.Sprintf("%d argument(s)", f.minArgs)
+	case f.maxArgs < 0:
+		return fmt.Sprintf("at least %d argument(s)", f.minArgs)
+	}
+	return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+}
+
+var functions = map[string]function{
+	"len":       {1, 1, fnLen},
+	"count":     {1, 2, fnCount},
+	"sum":       {1, 1, fnSum},
+	"min":       {1, -1, func(args []interface{}) (interface{}, error) { return extreme(args, -1) }},
+	"max":       {1, -1, func(args []interface{}) (interface{}, error) { return extreme(args, 1) }},
+	"join":      {2, 2, fnJoin},
+	"contains":  {2, 2, fnContains},
+	"coalesce":  {1, -1, fnCoalesce},
+	"string":    {1, 1, func(args []interface{}) (interface{}, error) { return mapScalar(args[0], fnString) }},
+	"int":       {1, 1, func(args []interface{}) (interface{}, error) { return mapScalar(args[0], fnInt) }},
+	"cpu":       {1, 1, func(args []interface{}) (interface{}, error) { return mapScalar(args[0], fnCPU) }},
+	"memory":    {1, 1, func(args []interface{}) (interface{}, error) { return mapScalar(args[0], fnMemory) }},
+	"condition": {2, 3, fnCondition},
+	"round":     {1, 2, fnRound},
+
+	// Status columns of kubectl get (status.go)
+	"pod_status":      {1, 1, objectFunc(podStatus)},
+	"node_status":     {1, 1, objectFunc(nodeStatus)},
+	"volume_status":   {1, 1, objectFunc(volumeStatus)},
+	"job_completions": {1, 1, objectFunc(jobCompletions)},
+}
+
+// Functions returns the names of the built-in functions.
+func Functions() []string {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	return names
+}
+
+// mapScalar applies fn to v, or to each element when v is a list.
+func mapScalar(v interface{}, fn func(interface{}) (interface{}, error)) (interface{}, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		if v == nil {
+			return nil, nil
+		}
+		return fn(v)
+	}
+	out := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		if item == nil {
+			continue
+		}
+		mapped, err := fn(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, mapped)
+	}
+	return out, nil
+}
+
+func fnLen(args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case string:
+		return int64(len(v)), nil
+	case map[string]interface{}:
+		return int64(len(v)), nil
+	}
+	return int64(len(toList(args[0]))), nil
+}
+
+// fnCount counts the non-null elements of a list, or with a second argument
+// the elements equal to it.
+func fnCount(args []interface{}) (interface{}, error) {
+	var n int64
+	for _, item := range toList(args[0]) {
+		if len(args) == 2 && equal(item, args[1]) || len(args) == 1 && item != nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// fnSum adds the numbers in a list; like SQL SUM it is null when there are none.
+func fnSum(args []interface{}) (interface{}, error) {
+	var total interface{}
+	for _, item := range toList(args[0]) {
+		if item == nil {
+			continue
+		}
+		if _, ok := toFloat(item); !ok {
+			return nil, fmt.Errorf("cannot sum %s", describe(item))
+		}
+		if total == nil {
+			total = item
+			continue
+		}
+		sum, err := arithmetic("+", total, item)
+		if err != nil {
+			return nil, err
+		}
+		total = sum
+	}
+	return total, nil
+}
+
+// extreme returns the smallest (sign -1) or largest (sign 1) value of a list
+// or of several arguments, ignoring nulls.
+func extreme(args []interface{}, sign int) (interface{}, error) {
+	values := args
+	if len(args) == 1 {
+		values = toList(args[0])
+	}
+	var best interface{}
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if best == nil {
+			best = v
+			continue
+		}
+		op := "<"
+		if sign > 0 {
+			op = ">"
+		}
+		better, err := compare(op, v, best)
+		if err != nil {
+			return nil, err
+		}
+		if better.(bool) {
+			best = v
+		}
+	}
+	return best, nil
+}
+
+func fnJoin(args []interface{}) (interface{}, error) {
+	sep, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("separator must be a string, got %s", describe(args[1]))
+	}
+	var parts []string
+	for _, item := range toList(args[0]) {
+		if item != nil {
+			parts = append(parts, toString(item))
+		}
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// fnContains reports whether a list holds a value or a string a substring.
+func fnContains(args []interface{}) (interface{}, error) {
+	if s, ok := args[0].(string); ok {
+		sub, ok := args[1].(string)
+		return ok && strings.Contains(s, sub), nil
+	}
+	for _, item := range toList(args[0]) {
+		if equal(item, args[1]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func fnCoalesce(args []interface{}) (interface{}, error) {
+	for _, v := range args {
+		if v != nil {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func fnString(v interface{}) (interface{}, error) {
+	return toString(v), nil
+}
+
+func fnInt(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case string:
+		if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			return n, nil
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return int64(f), nil
+		}
+		return nil, fmt.Errorf("invalid integer %q", v)
+	}
+	return nil, fmt.Errorf("cannot convert %s to int", describe(v))
+}
+
+// fnRound rounds a number to digits decimal places (default 0, giving an int).
+func fnRound(args []interface{}) (interface{}, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	f, ok := toFloat(args[0])
+	if !ok {
+		return nil, fmt.Errorf("cannot round %s", describe(args[0]))
+	}
+	var digits int64
+	if len(args) == 2 {
+		if digits, ok = args[1].(int64); !ok {
+			return nil, fmt.Errorf("digits must be an integer, got %s", describe(args[1]))
+		}
+	}
+	if digits == 0 {
+		return int64(math.Round(f)), nil
+	}
+	scale := math.Pow(10, float64(digits))
+	return math.Round(f*scale) / scale, nil
+}
+
+// fnCPU converts a CPU quantity ("250m", "0.5", 2) to millicores.
+func fnCPU(v interface{}) (interface{}, error) {
+	if f, ok := toFloat(v); ok {
+		return int64(f * 1000), nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid CPU quantity %s", describe(v))
+	}
+	millis, err := parser.ParseCPUToMillicores(s)
+	if err != nil {
+		return nil, err
+	}
+	return millis, nil
+}
+
+// fnMemory converts a memory quantity ("128Mi", "1Gi") to MiB.
+func fnMemory(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		if _, num := toFloat(v); !num {
+			return nil, fmt.Errorf("invalid memory quantity %s", describe(v))
+		}
+		s = toString(v)
+	}
+	mib, err := parser.ParseMemoryToMiB(s)
+	if err != nil {
+		return nil, err
+	}
+	return mib, nil
+}
+
+// fnCondition returns a field ("status" by default) of the condition with
+// the given type in a list of status conditions.
+func fnCondition(args []interface{}) (interface{}, error) {
+	condType, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("condition type must be a string, got %s", describe(args[1]))
+	}
+	key := "status"
+	if len(args) == 3 {
+		if key, ok = args[2].(string); !ok {
+			return nil, fmt.Errorf("condition key must be a string, got %s", describe(args[2]))
+		}
+	}
+	for _, item := range toList(args[0]) {
+		cond, ok := item.(map[string]interface{})
+		if ok && cond["type"] == condType {
+			return normalize(cond[key]), nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,337 @@
+// Package expr implements the small expression language used by computed
+// fields. An expression reads object paths and combines them with literals,
+// operators and a fixed set of functions; it cannot loop, assign or call out,
+// so plugin files can use it safely.
+//
+//	sum(.status.containerStatuses[*].restartCount)
+//	count(.status.containerStatuses[*].ready, true) + '/' + len(.spec.containers)
+//	sum(cpu(.spec.containers[*].resources.requests.cpu))
+//	condition(.status.conditions, 'Ready')
+//	.spec.suspend ? 'Suspended' : 'Active'
+//
+// Paths start with a dot and use the field path syntax: dotted keys, quoted
+// bracket keys (['x/y']), and [n] or [*] on arrays. A lone dot is the whole
+// object, for functions such as pod_status(.) that read many fields.
+// Operators, by increasing precedence, are ?:, ||, &&, comparisons
+// (== != < <= > >=), + -, * / % and unary ! -. Strings are quoted with ' or ".
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Resolver returns the value at an object path, or nil when it is missing.
+// The path is passed as written in the expression, leading dot included;
+// "." is the object itself.
+type Resolver func(path string) interface{}
+
+// Program is a compiled expression.
+type Program struct {
+	src  string
+	root node
+}
+
+// Compile parses src into a Program.
+func Compile(src string) (*Program, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (p *Program) String() string {
+	return p.src
+}
+
+// Eval evaluates the expression, reading paths through resolve. Results are
+// nil, bool, int64, float64, string or []interface{}; path values are
+// returned as resolved.
+func (p *Program) Eval(resolve Resolver) (interface{}, error) {
+	return p.root.eval(resolve)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokPath
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators, longest first so "<=" is not read as "<".
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "+", "-", "*", "/", "%", "!", "?", ":", "(", ")", ","}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		ch := src[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '\'' || ch == '"':
+			end := strings.IndexByte(src[i+1:], ch)
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			tokens = append(tokens, token{tokString, src[i+1 : i+1+end], i})
+			i += end + 2
+		case ch == '.' && (i+1 == len(src) || !isIdentChar(src[i+1]) && src[i+1] != '['):
+			// A lone dot is the whole object
+			tokens = append(tokens, token{tokPath, ".", i})
+			i++
+		case ch == '.':
+			start := i
+			for i < len(src) && (isIdentChar(src[i]) || src[i] == '.' || src[i] == '[') {
+				if src[i] == '[' {
+					end := closingBracket(src, i)
+					if end == -1 {
+						return nil, fmt.Errorf("unterminated [ in path at position %d", i+1)
+					}
+					i = end
+				}
+				i++
+			}
+			tokens = append(tokens, token{tokPath, src[start:i], start})
+		case ch >= '0' && ch <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, src[start:i], start})
+		case isIdentChar(ch):
+			start := i
+			for i < len(src) && isIdentChar(src[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, src[start:i], start})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at position %d", string(ch), i+1)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{tokEOF, "end of expression", len(src)}), nil
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// closingBracket returns the index of the ] closing the [ at open, skipping
+// a quoted key, or -1.
+func closingBracket(src string, open int) int {
+	i := open + 1
+	if i < len(src) && (src[i] == '\'' || src[i] == '"') {
+		end := strings.IndexByte(src[i+1:], src[i])
+		if end == -1 {
+			return -1
+		}
+		i += end + 2
+	}
+	end := strings.IndexByte(src[i:], ']')
+	if end == -1 {
+		return -1
+	}
+	return i + end
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the operators ops.
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at position %d, got %q", op, tok.pos+1, tok.text)
+	}
+	return nil
+}
+
+func (p *exprParser) parseExpr() (node, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return &ternaryNode{cond, then, otherwise}, nil
+}
+
+// precedence lists binary operators from loosest to tightest binding.
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *exprParser) parseBinary(level int) (node, error) {
+	if level == len(precedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(precedence[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op, left, right}
+	}
+}
+
+func (p *exprParser) parseUnary() (node, error) {
+	if op, ok := p.accept("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op, operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		if n, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return literalNode{n}, nil
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos+1)
+		}
+		return literalNode{f}, nil
+	case tokString:
+		return literalNode{tok.text}, nil
+	case tokPath:
+		return pathNode(tok.text), nil
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return literalNode{true}, nil
+		case "false":
+			return literalNode{false}, nil
+		case "null":
+			return literalNode{nil}, nil
+		}
+		return p.parseCall(tok)
+	case tokOp:
+		if tok.text == "(" {
+			inner, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+}
+
+func (p *exprParser) parseCall(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos+1)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []node
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if len(args) < fn.minArgs || fn.maxArgs >= 0 && len(args) > fn.maxArgs {
+		return nil, fmt.Errorf("%s() takes %s, got %d", name.text, fn.arity(), len(args))
+	}
+	return &callNode{name.text, fn, args}, nil
+}
@@ -0,0 +1,107 @@
+package expr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var pod = map[string]interface{}{
+	".spec.containers[*].resources.requests.cpu":    []interface{}{"250m", "1"},
+	".spec.containers[*].resources.requests.memory": "1Gi",
+	".spec.containers":                          []interface{}{map[string]interface{}{}, map[string]interface{}{}},
+	".status.containerStatuses[*].restartCount": []interface{}{int64(2), int64(3)},
+	".status.containerStatuses[*].ready":        []interface{}{true, false},
+	".status.conditions": []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "False", "reason": "ContainersNotReady"},
+	},
+	".metadata.annotations['example.com/team']": "payments",
+	".spec.suspend":  true,
+	".spec.replicas": 3,
+}
+
+func resolve(path string) interface{} {
+	return pod[path]
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want interface{}
+	}{
+		{"sum(.status.containerStatuses[*].restartCount)", int64(5)},
+		{"count(.status.containerStatuses[*].ready, true) + '/' + len(.spec.containers)", "1/2"},
+		{"sum(cpu(.spec.containers[*].resources.requests.cpu))", int64(1250)},
+		{"sum(memory(.spec.containers[*].resources.requests.memory))", int64(1024)},
+		{"condition(.status.conditions, 'Ready')", "False"},
+		{"condition(.status.conditions, 'Ready', 'reason')", "ContainersNotReady"},
+		{"condition(.status.conditions, 'Scheduled')", nil},
+		{".spec.suspend ? 'Suspended' : 'Active'", "Suspended"},
+		{".metadata.annotations['example.com/team']", "payments"},
+		{".spec.replicas * 2 + 1", int64(7)},
+		{"7 / 2", 3.5},
+		{"-(1 + 2) * 3", int64(-9)},
+		{".spec.replicas >= 3 && !.spec.missing", true},
+		{"coalesce(.spec.missing, 'none')", "none"},
+		{".spec.missing + 1", nil},
+		{"sum(.spec.missing)", nil},
+		{"max(.status.containerStatuses[*].restartCount)", int64(3)},
+		{"min(4, 2, 9)", int64(2)},
+		{"join(cpu(.spec.containers[*].resources.requests.cpu), ',')", "250,1000"},
+		{"contains(.status.containerStatuses[*].ready, false)", true},
+		{"int('42') + int(2.9)", int64(44)},
+		{"round(1250 * 100 / 1000)", int64(125)},
+		{"round(2 / 3, 2)", 0.67},
+		{"string(1.5) + \"x\"", "1.5x"},
+	}
+	for _, tt := range tests {
+		program, err := Compile(tt.src)
+		if err != nil {
+			t.Errorf("Compile(%q) failed: %v", tt.src, err)
+			continue
+		}
+		got, err := program.Eval(resolve)
+		if err != nil {
+			t.Errorf("Eval(%q) failed: %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Eval(%q) = %#v, want %#v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"sum(.a", `expected ")"`},
+		{"frobnicate(.a)", `unknown function "frobnicate"`},
+		{"len(.a, .b)", "len() takes 1 argument(s), got 2"},
+		{"'unterminated", "unterminated string"},
+		{".a ? 1", `expected ":"`},
+		{"replicas + 1", `unknown function "replicas"`},
+		{".a['x", "unterminated ["},
+		{"1 2", `unexpected "2"`},
+		{".a ~ 1", `unexpected "~"`},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%q) error = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	for _, src := range []string{"'a' * 2", "sum(.status.conditions)", "cpu('lots')", ".spec.containers < 1"} {
+		program, err := Compile(src)
+		if err != nil {
+			t.Fatalf("Compile(%q) failed: %v", src, err)
+		}
+		if _, err := program.Eval(resolve); err == nil {
+			t.Errorf("Eval(%q) should fail", src)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package expr
+
+import "fmt"
+
+// The functions in this file port the STATUS-style columns of kubectl's
+// table printers, so computed fields read the way kubectl get shows them.
+// Each takes the whole object: pod_status(.).
+
+// objectFunc adapts a printer to a function of one object argument.
+func objectFunc(printer func(obj map[string]interface{}) interface{}) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		obj, ok := args[0].(map[string]interface{})
+		if !ok {
+			if args[0] == nil {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("expected an object, got %s", describe(args[0]))
+		}
+		return printer(obj), nil
+	}
+}
+
+// podStatus returns the STATUS column of kubectl get pods: the phase, or
+// the reason a container is waiting or terminated (CrashLoopBackOff,
+// OOMKilled), init progress (Init:0/1) or Terminating.
+func podStatus(pod map[string]interface{}) interface{} {
+	status := mapAt(pod, "status")
+	reason := stringAt(status, "phase")
+	if r := stringAt(status, "reason"); r != "" {
+		reason = r
+	}
+
+	initContainers := sliceAt(mapAt(pod, "spec"), "initContainers")
+	initializing := false
+	for i, item := range sliceAt(status, "initContainerStatuses") {
+		container, _ := item.(map[string]interface{})
+		state := mapAt(container, "state")
+		terminated := mapAt(state, "terminated")
+		waiting := mapAt(state, "waiting")
+		switch {
+		case terminated != nil && intAt(terminated, "exitCode") == 0:
+			continue
+		case isSidecar(initContainers, i) && container["started"] == true:
+			continue
+		case terminated != nil:
+			reason = "Init:" + exitReason(terminated)
+		case waiting != nil && stringAt(waiting, "reason") != "" && stringAt(waiting, "reason") != "PodInitializing":
+			reason = "Init:" + stringAt(waiting, "reason")
+		default:
+			reason = fmt.Sprintf("Init:%d/%d", i, len(initContainers))
+		}
+		initializing = true
+		break
+	}
+
+	if !initializing {
+		hasRunning := false
+		statuses := sliceAt(status, "containerStatuses")
+		for i := len(statuses) - 1; i >= 0; i-- {
+			container, _ := statuses[i].(map[string]interface{})
+			state := mapAt(container, "state")
+			waiting := mapAt(state, "waiting")
+			terminated := mapAt(state, "terminated")
+			switch {
+			case waiting != nil && stringAt(waiting, "reason") != "":
+				reason = stringAt(waiting, "reason")
+			case terminated != nil:
+				reason = exitReason(terminated)
+			case container["ready"] == true && mapAt(state, "running") != nil:
+				hasRunning = true
+			}
+		}
+		// A completed container with others still running
+		if reason == "Completed" && hasRunning {
+			reason = "NotReady"
+			for _, item := range sliceAt(status, "conditions") {
+				if cond, _ := item.(map[string]interface{}); stringAt(cond, "type") == "Ready" && stringAt(cond, "status") == "True" {
+					reason = "Running"
+				}
+			}
+		}
+	}
+
+	if mapAt(pod, "metadata")["deletionTimestamp"] != nil {
+		if stringAt(status, "reason") == "NodeLost" {
+			return "Unknown"
+		}
+		return "Terminating"
+	}
+	return reason
+}
+
+// isSidecar reports whether init container i restarts like a regular
+// container (restartPolicy: Always).
+func isSidecar(initContainers []interface{}, i int) bool {
+	if i >= len(initContainers) {
+		return false
+	}
+	container, _ := initContainers[i].(map[string]interface{})
+	return stringAt(container, "restartPolicy") == "Always"
+}
+
+// exitReason describes a terminated container state.
+func exitReason(terminated map[string]interface{}) string {
+	if reason := stringAt(terminated, "reason"); reason != "" {
+		return reason
+	}
+	if signal := intAt(terminated, "signal"); signal != 0 {
+		return fmt.Sprintf("Signal:%d", signal)
+	}
+	return fmt.Sprintf("ExitCode:%d", intAt(terminated, "exitCode"))
+}
+
+// nodeStatus returns the STATUS column of kubectl get nodes: Ready, NotReady
+// or Unknown, plus SchedulingDisabled for cordoned nodes.
+func nodeStatus(node map[string]interface{}) interface{} {
+	status := "Unknown"
+	for _, item := range sliceAt(mapAt(node, "status"), "conditions") {
+		cond, _ := item.(map[string]interface{})
+		if stringAt(cond, "type") != "Ready" {
+			continue
+		}
+		status = "NotReady"
+		if stringAt(cond, "status") == "True" {
+			status = "Ready"
+		}
+	}
+	if mapAt(node, "spec")["unschedulable"] == true {
+		status += ",SchedulingDisabled"
+	}
+	return status
+}
+
+// volumeStatus returns the STATUS column of kubectl get pv and pvc: the
+// phase, or Terminating once deletion has started, followed by the REASON
+// column when there is one, as in "Failed,RecyclerFailed".
+func volumeStatus(volume map[string]interface{}) interface{} {
+	status := stringAt(mapAt(volume, "status"), "phase")
+	if mapAt(volume, "metadata")["deletionTimestamp"] != nil {
+		status = "Terminating"
+	}
+	if status == "" {
+		return nil
+	}
+	if reason := stringAt(mapAt(volume, "status"), "reason"); reason != "" {
+		status += "," + reason
+	}
+	return status
+}
+
+// jobCompletions returns the COMPLETIONS column of kubectl get jobs:
+// succeeded/completions, or succeeded/1 of parallelism for work queues.
+func jobCompletions(job map[string]interface{}) interface{} {
+	spec := mapAt(job, "spec")
+	succeeded := intAt(mapAt(job, "status"), "succeeded")
+	if _, ok := spec["completions"]; ok {
+		return fmt.Sprintf("%d/%d", succeeded, intAt(spec, "completions"))
+	}
+	if parallelism := intAt(spec, "parallelism"); parallelism > 1 {
+		return fmt.Sprintf("%d/1 of %d", succeeded, parallelism)
+	}
+	return fmt.Sprintf("%d/1", succeeded)
+}
+
+func mapAt(m map[string]interface{}, key string) map[string]interface{} {
+	v, _ := m[key].(map[string]interface{})
+	return v
+}
+
+func sliceAt(m map[string]interface{}, key string) []interface{} {
+	v, _ := m[key].([]interface{})
+	return v
+}
+
+func stringAt(m map[string]interface{}, key string) string {
+	v, _ := m[key].(string)
+	return v
+}
+
+func intAt(m map[string]interface{}, key string) int64 {
+	n, _ := normalize(m[key]).(int64)
+	if f, ok := m[key].(float64); ok {
+		n = int64(f)
+	}
+	return n
+}
@@ -0,0 +1,122 @@
+package expr
+
+import "testing"
+
+func evalObject(t *testing.T, src string, obj map[string]interface{}) interface{} {
+	t.Helper()
+	program, err := Compile(src)
+	if err != nil {
+		t.Fatalf("Compile(%q) failed: %v", src, err)
+	}
+	v, err := program.Eval(func(path string) interface{} {
+		if path == "." {
+			return obj
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Eval(%q) failed: %v", src, err)
+	}
+	return v
+}
+
+func containerState(state string, fields map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"state": map[string]interface{}{state: fields}}
+}
+
+func TestPodStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  map[string]interface{}
+		want string
+	}{
+		{"phase", map[string]interface{}{"status": map[string]interface{}{"phase": "Pending"}}, "Pending"},
+		{"crash loop", map[string]interface{}{"status": map[string]interface{}{
+			"phase": "Running",
+			"containerStatuses": []interface{}{
+				containerState("running", map[string]interface{}{}),
+				containerState("waiting", map[string]interface{}{"reason": "CrashLoopBackOff"}),
+			},
+		}}, "CrashLoopBackOff"},
+		{"oom killed", map[string]interface{}{"status": map[string]interface{}{
+			"phase":             "Running",
+			"containerStatuses": []interface{}{containerState("terminated", map[string]interface{}{"reason": "OOMKilled", "exitCode": int64(137)})},
+		}}, "OOMKilled"},
+		{"exit code", map[string]interface{}{"status": map[string]interface{}{
+			"phase":             "Failed",
+			"containerStatuses": []interface{}{containerState("terminated", map[string]interface{}{"exitCode": int64(2)})},
+		}}, "ExitCode:2"},
+		{"init progress", map[string]interface{}{
+			"spec": map[string]interface{}{"initContainers": []interface{}{map[string]interface{}{}, map[string]interface{}{}}},
+			"status": map[string]interface{}{
+				"phase": "Pending",
+				"initContainerStatuses": []interface{}{
+					containerState("terminated", map[string]interface{}{"exitCode": int64(0)}),
+					containerState("running", map[string]interface{}{}),
+				},
+			},
+		}, "Init:1/2"},
+		{"init crash", map[string]interface{}{
+			"spec": map[string]interface{}{"initContainers": []interface{}{map[string]interface{}{}}},
+			"status": map[string]interface{}{
+				"phase":                 "Pending",
+				"initContainerStatuses": []interface{}{containerState("waiting", map[string]interface{}{"reason": "CrashLoopBackOff"})},
+			},
+		}, "Init:CrashLoopBackOff"},
+		{"sidecar started", map[string]interface{}{
+			"spec": map[string]interface{}{"initContainers": []interface{}{map[string]interface{}{"restartPolicy": "Always"}}},
+			"status": map[string]interface{}{
+				"phase":                 "Running",
+				"initContainerStatuses": []interface{}{map[string]interface{}{"started": true, "state": map[string]interface{}{"running": map[string]interface{}{}}}},
+			},
+		}, "Running"},
+		{"evicted", map[string]interface{}{"status": map[string]interface{}{"phase": "Failed", "reason": "Evicted"}}, "Evicted"},
+		{"terminating", map[string]interface{}{
+			"metadata": map[string]interface{}{"deletionTimestamp": "2024-01-01T00:00:00Z"},
+			"status":   map[string]interface{}{"phase": "Running"},
+		}, "Terminating"},
+	}
+	for _, tt := range tests {
+		if got := evalObject(t, "pod_status(.)", tt.pod); got != tt.want {
+			t.Errorf("%s: pod_status = %v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNodeVolumeJobStatus(t *testing.T) {
+	ready := map[string]interface{}{
+		"spec":   map[string]interface{}{"unschedulable": true},
+		"status": map[string]interface{}{"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}}},
+	}
+	notReady := map[string]interface{}{
+		"status": map[string]interface{}{"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "Unknown"}}},
+	}
+	tests := []struct {
+		src  string
+		obj  map[string]interface{}
+		want string
+	}{
+		{"node_status(.)", ready, "Ready,SchedulingDisabled"},
+		{"node_status(.)", notReady, "NotReady"},
+		{"node_status(.)", map[string]interface{}{}, "Unknown"},
+		{"volume_status(.)", map[string]interface{}{"status": map[string]interface{}{"phase": "Bound"}}, "Bound"},
+		{"volume_status(.)", map[string]interface{}{
+			"metadata": map[string]interface{}{"deletionTimestamp": "2024-01-01T00:00:00Z"},
+			"status":   map[string]interface{}{"phase": "Bound"},
+		}, "Terminating"},
+		{"volume_status(.)", map[string]interface{}{
+			"status": map[string]interface{}{"phase": "Failed", "reason": "RecyclerFailed"},
+		}, "Failed,RecyclerFailed"},
+		{"job_completions(.)", map[string]interface{}{
+			"spec":   map[string]interface{}{"completions": int64(3)},
+			"status": map[string]interface{}{"succeeded": int64(1)},
+		}, "1/3"},
+		{"job_completions(.)", map[string]interface{}{"spec": map[string]interface{}{"parallelism": int64(4)}}, "0/1 of 4"},
+		{"job_completions(.)", map[string]interface{}{"spec": map[string]interface{}{}}, "0/1"},
+	}
+	for _, tt := range tests {
+		if got := evalObject(t, tt.src, tt.obj); got != tt.want {
+			t.Errorf("%s = %v, want %s", tt.src, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package manifest loads Kubernetes objects from YAML and JSON manifests, so
+// queries can run against files instead of a cluster.
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Stdin is the path that reads manifests from standard input.
+const Stdin = "-"
+
+// Load reads the objects in paths: files, directories (searched recursively
+// for .yaml, .yml and .json files) or Stdin, which reads stdin.
+func Load(paths []string, stdin io.Reader) ([]unstructured.Unstructured, error) {
+	var objects []unstructured.Unstructured
+	for _, path := range paths {
+		if path == Stdin {
+			items, err := Read(stdin)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read manifests from stdin: %w", err)
+			}
+			objects = append(objects, items...)
+			continue
+		}
+
+		files, err := Files(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			items, err := ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, items...)
+		}
+	}
+	return objects, nil
+}
+
+// Files returns path if it is a file, or the manifest files under it in
+// lexical order if it is a directory.
+func Files(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".yaml", ".yml", ".json":
+			if !d.IsDir() {
+				files = append(files, p)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests in %s: %w", path, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadFile reads the objects in one manifest file.
+func ReadFile(path string) ([]unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+	defer f.Close()
+
+	objects, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests in %s: %w", path, err)
+	}
+	return objects, nil
+}
+
+// Read decodes a stream of YAML documents or JSON objects. Items of List
+// objects (kind v1/List or any *List with items) are returned in place of
+// the list. Documents that are empty or are not Kubernetes objects (no
+// apiVersion or kind, as in Helm values files) are skipped.
+func Read(r io.Reader) ([]unstructured.Unstructured, error) {
+	return ReadAs(r, schema.GroupVersionKind{})
+}
+
+// ReadAs is Read for manifests whose objects may not name their kind, as
+// in cluster dumps: documents without apiVersion and kind are objects of
+// kind, or lists of them if they have items.
+func ReadAs(r io.Reader, kind schema.GroupVersionKind) ([]unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	var objects []unstructured.Unstructured
+	for doc := 1; ; doc++ {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		if _, hasKind := obj["kind"]; !hasKind && obj != nil && !kind.Empty() {
+			obj["apiVersion"], obj["kind"] = kind.GroupVersion().String(), kind.Kind
+			if _, isList := obj["items"]; isList {
+				obj["kind"] = kind.Kind + "List"
+			}
+		}
+		objects = append(objects, flatten(obj)...)
+	}
+}
+
+// flatten returns obj, or the items of obj if it is a list. Items without
+// a kind, as in typed lists such as PodList, take it from the list.
+func flatten(obj map[string]interface{}) []unstructured.Unstructured {
+	if obj == nil {
+		return nil
+	}
+	u := unstructured.Unstructured{Object: obj}
+	if u.GetAPIVersion() == "" || u.GetKind() == "" {
+		return nil
+	}
+	items, isList := obj["items"].([]interface{})
+	if !isList || !strings.HasSuffix(u.GetKind(), "List") {
+		return []unstructured.Unstructured{u}
+	}
+
+	itemKind := strings.TrimSuffix(u.GetKind(), "List")
+	var objects []unstructured.Unstructured
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, hasKind := m["kind"]; !hasKind && itemKind != "" {
+			m["kind"] = itemKind
+			m["apiVersion"] = u.GetAPIVersion()
+		}
+		objects = append(objects, flatten(m)...)
+	}
+	return objects
+}
+
+// Write writes objects as one JSON List, which Read reads back.
+func Write(w io.Writer, objects []unstructured.Unstructured) error {
+	items := make([]interface{}, len(objects))
+	for i := range objects {
+		items[i] = objects[i].Object
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items})
+}
@@ -0,0 +1,111 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func names(t *testing.T, paths []string, stdin string) []string {
+	t.Helper()
+	objects, err := Load(paths, strings.NewReader(stdin))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var got []string
+	for _, obj := range objects {
+		got = append(got, obj.GetKind()+"/"+obj.GetName())
+	}
+	return got
+}
+
+func TestRead(t *testing.T) {
+	stdin := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+# values, not an object
+replicas: 3
+---
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+`
+	got := strings.Join(names(t, []string{Stdin}, stdin), ",")
+	if want := "Deployment/web,Service/web,ConfigMap/config"; got != want {
+		t.Errorf("Read() = %s, want %s", got, want)
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	// Items of typed lists may leave out their kind
+	stdin := `{"apiVersion": "v1", "kind": "PodList", "items": [{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "a"}}, {"metadata": {"name": "b"}}]}
+{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "c"}}`
+	got := strings.Join(names(t, []string{Stdin}, stdin), ",")
+	if want := "Pod/a,Pod/b,Pod/c"; got != want {
+		t.Errorf("Read() = %s, want %s", got, want)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	_, err := Load([]string{Stdin}, strings.NewReader("apiVersion: v1\nkind: Pod\n---\nkind: [\n"))
+	if err == nil || !strings.Contains(err.Error(), "document 2") {
+		t.Errorf("Load() error = %v, want an error for document 2", err)
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b/service.yml":   "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n",
+		"a/deploy.yaml":   "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n",
+		"c/pod.json":      `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "p"}}`,
+		"README.md":       "apiVersion: v1\nkind: Secret\n",
+		"kustomization.x": "resources: []\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := strings.Join(names(t, []string{dir, filepath.Join(dir, "b/service.yml")}, ""), ",")
+	if want := "Deployment/web,Service/web,Pod/p,Service/web"; got != want {
+		t.Errorf("Load() = %s, want %s", got, want)
+	}
+
+	if _, err := Load([]string{filepath.Join(dir, "missing")}, nil); err == nil {
+		t.Error("Load() of a missing path succeeded")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	objects, err := Read(strings.NewReader("apiVersion: v1\nkind: Pod\nmetadata:\n  name: a\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := Write(&b, objects); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got := strings.Join(names(t, []string{Stdin}, b.String()), ",")
+	if want := "Pod/a,Deployment/b"; got != want {
+		t.Errorf("Read(Write()) = %s, want %s", got, want)
+	}
+}
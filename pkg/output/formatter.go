@@ -24,9 +24,10 @@ const (
 )
 
 type Formatter struct {
-	format  Format
-	writer  io.Writer
-	elapsed time.Duration
+	format      Format
+	writer      io.Writer
+	elapsed     time.Duration
+	cacheStatus string
 }
 
 func NewFormatter(format Format) *Formatter {
@@ -41,6 +42,12 @@ func (f *Formatter) SetElapsed(d time.Duration) {
 	f.elapsed = d
 }
 
+// SetCacheStatus sets whether results came from the cache ("cached",
+// "partly cached") to display in output footer.
+func (f *Formatter) SetCacheStatus(status string) {
+	f.cacheStatus = status
+}
+
 func (f *Formatter) Print(results []map[string]interface{}, fields []string) error {
 	switch f.format {
 	case FormatJSON:
@@ -173,8 +180,15 @@ func (f *Formatter) printWide(results []map[string]interface{}, fields []string)
 
 // printFooter prints the resource count line with optional elapsed time.
 func (f *Formatter) printFooter(count int) {
+	var notes []string
 	if f.elapsed > 0 {
-		fmt.Fprintf(f.writer, "\n%d resource(s) found. (%.2fs)\n", count, f.elapsed.Seconds())
+		notes = append(notes, fmt.Sprintf("%.2fs", f.elapsed.Seconds()))
+	}
+	if f.cacheStatus != "" {
+		notes = append(notes, f.cacheStatus)
+	}
+	if len(notes) > 0 {
+		fmt.Fprintf(f.writer, "\n%d resource(s) found. (%s)\n", count, strings.Join(notes, ", "))
 	} else {
 		fmt.Fprintf(f.writer, "\n%d resource(s) found.\n", count)
 	}
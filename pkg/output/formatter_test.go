@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"strings"
+	"time"
 	"testing"
 )
 
@@ -214,3 +215,17 @@ func TestPrintTable(t *testing.T) {
 		t.Error("Expected '2 resource(s) found' in table output")
 	}
 }
+
+func TestPrintFooterCacheStatus(t *testing.T) {
+	var buf bytes.Buffer
+	f := &Formatter{format: FormatTable, writer: &buf}
+	f.SetElapsed(1500 * time.Millisecond)
+	f.SetCacheStatus("cached")
+
+	if err := f.Print([]map[string]interface{}{{"name": "pod-1"}}, []string{"name"}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "1 resource(s) found. (1.50s, cached)") {
+		t.Errorf("Expected the cache status in the footer, got %q", buf.String())
+	}
+}
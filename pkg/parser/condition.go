@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type ConditionOperator string
@@ -33,6 +34,8 @@ type Condition struct {
 	Field          string
 	Operator       ConditionOperator
 	Value          string
+	Param          string   // placeholder the value is bound from (":ns", "$1"); cleared by Bind
+	Values         []string // IN/NOT IN list set by Bind; nil means the list is read from Value
 	SubQuery       *Query   // parsed subquery for IN/NOT IN
 	SubQueryValues []string // resolved values from executor (runtime)
 }
@@ -109,6 +112,9 @@ func parseCondition(condStr string) (*Condition, error) {
 			cond.Field = strings.TrimSpace(condStr[:idx])
 			cond.Value = strings.TrimSpace(condStr[idx+len(pattern):])
 			cond.Operator = canonicalOp
+			if isPlaceholder(cond.Value) {
+				cond.Param = cond.Value
+			}
 			cond.Value = strings.Trim(cond.Value, "'\"")
 			return cond, nil
 		}
@@ -144,6 +150,11 @@ func parseCondition(condStr string) (*Condition, error) {
 				cond.SubQuery = subQuery
 			}
 
+			// A quoted placeholder is a literal value
+			if isPlaceholder(cond.Value) {
+				cond.Param = cond.Value
+			}
+
 			// Remove quotes
 			cond.Value = strings.Trim(cond.Value, "'\"")
 
@@ -241,9 +252,8 @@ func (c *Condition) Evaluate(value interface{}) bool {
 			}
 			return false
 		}
-		inValues := strings.Split(strings.Trim(c.Value, "()"), ",")
-		for _, v := range inValues {
-			if strings.TrimSpace(strings.Trim(v, "'\"")) == valStr {
+		for _, v := range c.listValues() {
+			if v == valStr {
 				return true
 			}
 		}
@@ -257,9 +267,8 @@ func (c *Condition) Evaluate(value interface{}) bool {
 			}
 			return true
 		}
-		inValues := strings.Split(strings.Trim(c.Value, "()"), ",")
-		for _, v := range inValues {
-			if strings.TrimSpace(strings.Trim(v, "'\"")) == valStr {
+		for _, v := range c.listValues() {
+			if v == valStr {
 				return false
 			}
 		}
@@ -277,8 +286,28 @@ func (c *Condition) Evaluate(value interface{}) bool {
 	return false
 }
 
+// listValues returns the values of an IN or NOT IN list: those bound by
+// Bind, or else the comma-separated items of Value with quotes removed.
+func (c *Condition) listValues() []string {
+	if c.Values != nil {
+		return c.Values
+	}
+	items := strings.Split(strings.Trim(c.Value, "()"), ",")
+	for i, item := range items {
+		items[i] = strings.Trim(strings.TrimSpace(item), "'\"")
+	}
+	return items
+}
+
 func compareValues(a, b string) int {
-	// Try numeric comparison first
+	// Compare timestamps, including now()-relative values, as times
+	if bTime, ok := ParseTimeValue(b); ok {
+		if aTime, ok := ParseTimeValue(a); ok {
+			return aTime.Compare(bTime)
+		}
+	}
+
+	// Then numeric comparison
 	aNum, aErr := strconv.ParseFloat(a, 64)
 	bNum, bErr := strconv.ParseFloat(b, 64)
 	if aErr == nil && bErr == nil {
@@ -300,6 +329,32 @@ func compareValues(a, b string) int {
 	return 0
 }
 
+// String returns the condition as written, e.g. "status != Running". A
+// subquery is shown as its text.
+func (c *Condition) String() string {
+	if c.Values != nil {
+		return fmt.Sprintf("%s %s (%s)", c.Field, c.Operator, strings.Join(c.Values, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", c.Field, c.Operator, c.Value)
+}
+
+// String returns the conditions joined by the group's operator, with
+// subgroups in parentheses, e.g. "ns = prod AND (status = Failed OR restarts > 5)".
+func (g *ConditionGroup) String() string {
+	var parts []string
+	for i := range g.Conditions {
+		parts = append(parts, g.Conditions[i].String())
+	}
+	for _, sub := range g.SubGroups {
+		if len(sub.Conditions)+len(sub.SubGroups) > 1 {
+			parts = append(parts, "("+sub.String()+")")
+		} else {
+			parts = append(parts, sub.String())
+		}
+	}
+	return strings.Join(parts, " "+string(g.LogicalOperator)+" ")
+}
+
 func (g *ConditionGroup) Evaluate(obj map[string]interface{}) bool {
 	if g.LogicalOperator == LogicalAnd {
 		for _, cond := range g.Conditions {
@@ -328,3 +383,26 @@ func (g *ConditionGroup) Evaluate(obj map[string]interface{}) bool {
 	}
 	return len(g.Conditions) == 0 && len(g.SubGroups) == 0
 }
+
+var nowRe = regexp.MustCompile(`(?i)^now\(\)\s*(?:([+-])\s*(\d+)([smhd]))?$`)
+
+// ParseTimeValue parses an RFC 3339 timestamp or a time relative to the
+// current one: now(), now()-1h, now()+30m. Units are s, m, h and d.
+func ParseTimeValue(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if m := nowRe.FindStringSubmatch(value); m != nil {
+		t := time.Now()
+		if m[1] == "" {
+			return t, true
+		}
+		n, _ := strconv.Atoi(m[2])
+		unit := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour, "d": 24 * time.Hour}[strings.ToLower(m[3])]
+		offset := time.Duration(n) * unit
+		if m[1] == "-" {
+			offset = -offset
+		}
+		return t.Add(offset), true
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	return t, err == nil
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"testing"
+	"time"
 )
 
 func TestParseSimpleCondition(t *testing.T) {
@@ -134,6 +135,30 @@ func TestEvaluateGreaterThan(t *testing.T) {
 	}
 }
 
+func TestEvaluateTimeValues(t *testing.T) {
+	recent := time.Now().Add(-30 * time.Minute).UTC().Format(time.RFC3339Nano)
+	old := time.Now().Add(-3 * time.Hour).UTC().Format(time.RFC3339)
+
+	cond := Condition{Field: "ts", Operator: OpGreaterThan, Value: "now()-1h"}
+	if !cond.Evaluate(recent) || cond.Evaluate(old) {
+		t.Errorf("Expected only %s to be after now()-1h", recent)
+	}
+	cond = Condition{Field: "ts", Operator: OpLessThan, Value: "now() - 2d"}
+	if cond.Evaluate(old) {
+		t.Error("Expected 3h ago not to be before now() - 2d")
+	}
+
+	// Fractional seconds compare as times, not as text
+	cond = Condition{Field: "ts", Operator: OpGreaterThan, Value: "2024-01-01T10:00:00Z"}
+	if !cond.Evaluate("2024-01-01T10:00:00.5Z") {
+		t.Error("Expected 10:00:00.5 to be after 10:00:00")
+	}
+
+	if _, ok := ParseTimeValue("now()-1w"); ok {
+		t.Error("Expected unknown unit to be rejected")
+	}
+}
+
 func TestEvaluateGroupAnd(t *testing.T) {
 	group := &ConditionGroup{
 		LogicalOperator: LogicalAnd,
@@ -378,3 +403,24 @@ func TestParseEmptyCondition(t *testing.T) {
 		t.Errorf("Expected AND operator for empty conditions")
 	}
 }
+
+func TestConditionGroupString(t *testing.T) {
+	for _, input := range []string{
+		"status = Running",
+		"namespace = prod AND restarts > 5",
+		"status = Failed OR restarts > 5 AND namespace = prod",
+		"name IN (SELECT name FROM pod WHERE status = Pending)",
+	} {
+		group, err := ParseConditions(input)
+		if err != nil {
+			t.Fatalf("ParseConditions(%q) failed: %v", input, err)
+		}
+		want := input
+		if input == "status = Failed OR restarts > 5 AND namespace = prod" {
+			want = "status = Failed OR (restarts > 5 AND namespace = prod)"
+		}
+		if got := group.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// placeholderRe matches a parameter placeholder: :name or $n.
+var placeholderRe = regexp.MustCompile(`^(?::([A-Za-z_]\w*)|\$([1-9]\d*))$`)
+
+func isPlaceholder(token string) bool {
+	return placeholderRe.MatchString(token)
+}
+
+// paramName returns the key placeholder is looked up by in the params of
+// Bind: "ns" for ":ns", "1" for "$1".
+func paramName(placeholder string) string {
+	m := placeholderRe.FindStringSubmatch(placeholder)
+	return m[1] + m[2]
+}
+
+// Bind sets the placeholders in the conditions of query, its subqueries,
+// WITH queries and set operands to their values in params, keyed as
+// paramName returns. Unquoted :name and $n stand for a whole value, or an
+// item of an IN list. Values are never parsed, so quotes, commas and keywords
+// in them are compared literally. A placeholder without a value is an error;
+// unused params are ignored.
+func Bind(query *Query, params map[string]string) error {
+	bound := false
+	for _, group := range []*ConditionGroup{query.Conditions, query.Having} {
+		n, err := bindGroup(group, params)
+		if err != nil {
+			return err
+		}
+		bound = bound || n
+	}
+	// Bound namespaces scope the lists as literal ones do
+	if bound && query.Conditions != nil {
+		extractNamespace(query, query.Conditions)
+		extractJoinNamespaces(query, query.Conditions)
+	}
+
+	for _, cte := range query.With {
+		if err := Bind(cte.Query, params); err != nil {
+			return err
+		}
+	}
+	for _, op := range query.SetOps {
+		if err := Bind(op.Query, params); err != nil {
+			return err
+		}
+	}
+	if query.LogsOf != nil {
+		return Bind(query.LogsOf, params)
+	}
+	return nil
+}
+
+// bindGroup binds the placeholders of group and reports whether it had any.
+func bindGroup(group *ConditionGroup, params map[string]string) (bool, error) {
+	if group == nil {
+		return false, nil
+	}
+	bound := false
+	for i := range group.Conditions {
+		cond := &group.Conditions[i]
+		if cond.SubQuery != nil {
+			if err := Bind(cond.SubQuery, params); err != nil {
+				return false, err
+			}
+			continue
+		}
+		n, err := bindCondition(cond, params)
+		if err != nil {
+			return false, err
+		}
+		bound = bound || n
+	}
+	for _, sub := range group.SubGroups {
+		n, err := bindGroup(sub, params)
+		if err != nil {
+			return false, err
+		}
+		bound = bound || n
+	}
+	return bound, nil
+}
+
+func bindCondition(cond *Condition, params map[string]string) (bool, error) {
+	list := cond.Operator == OpIn || cond.Operator == OpNotIn
+	if cond.Param != "" {
+		value, err := lookupParam(cond.Param, params)
+		if err != nil {
+			return false, err
+		}
+		cond.Value, cond.Param = value, ""
+		if list {
+			cond.Values = []string{value}
+		}
+		return true, nil
+	}
+	if !list || cond.Values != nil {
+		return false, nil
+	}
+
+	bound := false
+	items := strings.Split(strings.Trim(cond.Value, "()"), ",")
+	values := make([]string, len(items))
+	for i, item := range items {
+		item = strings.TrimSpace(item)
+		if !isPlaceholder(item) {
+			values[i] = strings.Trim(item, "'\"")
+			continue
+		}
+		value, err := lookupParam(item, params)
+		if err != nil {
+			return false, err
+		}
+		values[i], bound = value, true
+	}
+	if bound {
+		cond.Values = values
+	}
+	return bound, nil
+}
+
+// CheckBound returns the error Bind would for the first placeholder of query
+// that has no value yet, or nil if every placeholder is bound.
+func CheckBound(query *Query) error {
+	return Bind(query, nil)
+}
+
+func lookupParam(placeholder string, params map[string]string) (string, error) {
+	value, ok := params[paramName(placeholder)]
+	if !ok {
+		return "", fmt.Errorf("missing value for parameter %s", placeholder)
+	}
+	return value, nil
+}
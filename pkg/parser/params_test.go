@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBind(t *testing.T) {
+	query, err := Parse("name FROM pod WHERE namespace = :ns AND restarts GT $1 AND name != ':ns'")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if query.Namespace != "" {
+		t.Errorf("Namespace before Bind = %q, want empty", query.Namespace)
+	}
+
+	if err := Bind(query, map[string]string{"ns": "prod", "1": "5"}); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	if query.Namespace != "prod" {
+		t.Errorf("Namespace = %q, want prod", query.Namespace)
+	}
+	var got []string
+	for _, cond := range query.Conditions.Conditions {
+		got = append(got, cond.String()+" "+cond.Param)
+	}
+	// A quoted placeholder is a literal value
+	want := []string{"namespace = prod ", "restarts > 5 ", "name != :ns "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("conditions = %q, want %q", got, want)
+	}
+}
+
+func TestBindLiteralValues(t *testing.T) {
+	// Values with quotes, keywords or commas must not change the query
+	values := []string{
+		"web' OR name = 'api",
+		"Running ORDER BY name LIMIT 1",
+		"a, b",
+		"(x)",
+	}
+	for _, value := range values {
+		query, err := Parse("name FROM pod WHERE status = :v AND name IN (:v, other) LIMIT 10")
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if err := Bind(query, map[string]string{"v": value}); err != nil {
+			t.Fatalf("Bind(%q) failed: %v", value, err)
+		}
+		if query.Limit != 10 || len(query.OrderBy) != 0 || len(query.Conditions.Conditions) != 2 {
+			t.Errorf("Bind(%q) changed the query: %+v", value, query)
+		}
+		status, in := query.Conditions.Conditions[0], query.Conditions.Conditions[1]
+		if !status.Evaluate(value) || status.Evaluate("web") {
+			t.Errorf("Bind(%q): status condition does not match the value literally", value)
+		}
+		if !in.Evaluate(value) || !in.Evaluate("other") || in.Evaluate("a") {
+			t.Errorf("Bind(%q): IN list = %q", value, in.Values)
+		}
+	}
+}
+
+func TestBindList(t *testing.T) {
+	query, err := Parse("name FROM pod WHERE name NOT IN :names")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if err := Bind(query, map[string]string{"names": "a,b"}); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	cond := query.Conditions.Conditions[0]
+	if cond.Evaluate("a,b") || !cond.Evaluate("a") {
+		t.Errorf("NOT IN :names binds %q, want one value", cond.Values)
+	}
+}
+
+func TestBindNested(t *testing.T) {
+	query, err := Parse("WITH d AS (SELECT name FROM deployment WHERE namespace = :ns) " +
+		"SELECT p.name FROM pod p JOIN service s ON p.labels.app = s.selector.app " +
+		"WHERE s.namespace = $1 AND p.name IN (SELECT name FROM d WHERE name != :skip) " +
+		"UNION name FROM service WHERE namespace = :ns")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if err := Bind(query, map[string]string{"ns": "prod", "1": "edge", "skip": "web-1"}); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	if got := query.With[0].Query.Namespace; got != "prod" {
+		t.Errorf("WITH namespace = %q, want prod", got)
+	}
+	if got := query.Joins[0].Namespace; got != "edge" {
+		t.Errorf("JOIN namespace = %q, want edge", got)
+	}
+	if got := query.Conditions.Conditions[1].SubQuery.Conditions.Conditions[0].Value; got != "web-1" {
+		t.Errorf("subquery value = %q, want web-1", got)
+	}
+	if got := query.SetOps[0].Query.Namespace; got != "prod" {
+		t.Errorf("UNION namespace = %q, want prod", got)
+	}
+}
+
+func TestBindMissing(t *testing.T) {
+	tests := []string{
+		"name FROM pod WHERE namespace = :ns",
+		"name FROM pod WHERE name IN (a, $2)",
+		"namespace, COUNT as pods FROM pod GROUP BY namespace HAVING pods > :min",
+		"name FROM pod WHERE name IN (SELECT name FROM service WHERE namespace = :ns)",
+	}
+	for _, input := range tests {
+		query, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", input, err)
+		}
+		err = Bind(query, map[string]string{"1": "unused"})
+		if err == nil || !strings.Contains(err.Error(), "missing value for parameter") {
+			t.Errorf("Bind(%q) error = %v, want missing value", input, err)
+		}
+	}
+}
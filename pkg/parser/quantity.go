@@ -8,7 +8,8 @@ import (
 )
 
 // ParseCPUToMillicores converts Kubernetes CPU quantity to millicores
-// Examples: "100m" -> 100, "0.5" -> 500, "1" -> 1000, "2.5" -> 2500
+// Examples: "100m" -> 100, "0.5" -> 500, "1" -> 1000, "2.5" -> 2500,
+// "250000000n" -> 250 (metrics API usage is in nano- or microcores)
 func ParseCPUToMillicores(value string) (int64, error) {
 	if value == "" {
 		return 0, nil
@@ -16,6 +17,17 @@ func ParseCPUToMillicores(value string) (int64, error) {
 
 	value = strings.TrimSpace(value)
 
+	// Handle nanocore and microcore formats (e.g., "12345678n", "1500u")
+	for suffix, perMilli := range map[string]int64{"n": 1000000, "u": 1000} {
+		if strings.HasSuffix(value, suffix) {
+			units, err := strconv.ParseInt(strings.TrimSuffix(value, suffix), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid CPU quantity: %s", value)
+			}
+			return units / perMilli, nil
+		}
+	}
+
 	// Handle millicore format (e.g., "100m")
 	if strings.HasSuffix(value, "m") {
 		millis := strings.TrimSuffix(value, "m")
@@ -17,6 +17,10 @@ func TestParseCPUToMillicores(t *testing.T) {
 		{"0.1", 100, false},
 		{"", 0, false},
 		{"  250m  ", 250, false},
+		{"250000000n", 250, false},
+		{"1500u", 1, false},
+		{"12n", 0, false},
+		{"xn", 0, true},
 		{"invalid", 0, true},
 	}
 
@@ -47,6 +51,7 @@ func TestParseMemoryToMiB(t *testing.T) {
 		{"1Ti", 1048576, false},
 		{"", 0, false},
 		{"  256Mi  ", 256, false},
+		{"512", 512, false}, // a bare number is MiB
 		{"100M", 95, false}, // 100MB ≈ 95.37 MiB
 		{"1G", 953, false},  // 1GB ≈ 953.67 MiB
 		{"invalid", 0, true},
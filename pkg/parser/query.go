@@ -23,6 +23,7 @@ type JoinClause struct {
 	Type       JoinType
 	Resource   string
 	Alias      string
+	Namespace  string // from a top-level "alias.namespace = value" condition; empty inherits the query namespace
 	Conditions []JoinCondition
 	// Deprecated: use Conditions instead. Kept for backward compatibility.
 	LeftField  string
@@ -40,11 +41,43 @@ type OrderByField struct {
 	Descending bool
 }
 
+// SetOperator combines the results of two queries.
+type SetOperator string
+
+const (
+	Union     SetOperator = "UNION"
+	Intersect SetOperator = "INTERSECT"
+	Except    SetOperator = "EXCEPT"
+)
+
+// SetOperation applies Operator between the rows produced so far and Query.
+// Without All, duplicate rows are removed as in SQL.
+type SetOperation struct {
+	Operator SetOperator
+	All      bool
+	Query    *Query
+}
+
+// CTE is a named query from a WITH clause. Its result can be used in FROM
+// and JOIN like a resource.
+type CTE struct {
+	Name  string
+	Query *Query
+}
+
+// ObjectRef names a single object as "resource/name", e.g. deployment/web.
+type ObjectRef struct {
+	Resource string
+	Name     string
+}
+
 type Query struct {
 	Fields        []string
 	Aggregates    []AggregateFunc
 	Resource      string
 	ResourceAlias string
+	DescendantsOf *ObjectRef // set by "FROM DESCENDANTS OF resource/name"
+	LogsOf        *Query     // pods to read logs from, set by "FROM logs(query)"
 	Namespace     string
 	Labels        map[string]string
 	FieldSelector string
@@ -56,10 +89,271 @@ type Query struct {
 	Limit         int
 	Offset        int
 	Distinct      bool
-	UseDefault    bool // true when user omits field list
+	UseDefault    bool           // true when user omits field list
+	With          []CTE          // WITH name AS (query), evaluated before this query
+	SetOps        []SetOperation // UNION / INTERSECT / EXCEPT operands, applied left to right
+	Explain       bool           // EXPLAIN: return the query plan instead of the rows
+	Analyze       bool           // EXPLAIN ANALYZE: run the query, adding row counts and timings to the plan
 }
 
+// Prefix returns the name the primary resource's fields are qualified with
+// in JOIN queries: the FROM alias if given, otherwise the resource name.
+func (q *Query) Prefix() string {
+	if q.ResourceAlias != "" {
+		return q.ResourceAlias
+	}
+	return q.Resource
+}
+
+// Prefix returns the name the joined resource's fields are qualified with:
+// the JOIN alias if given, otherwise the resource name.
+func (j *JoinClause) Prefix() string {
+	if j.Alias != "" {
+		return j.Alias
+	}
+	return j.Resource
+}
+
+// Parse parses a full query: an optional EXPLAIN [ANALYZE] prefix and WITH
+// list, then one or more SELECT queries combined with UNION, INTERSECT or EXCEPT.
 func Parse(input string) (*Query, error) {
+	input = strings.TrimSpace(input)
+	explain := regexp.MustCompile(`(?i)^EXPLAIN\s+(ANALYZE\s+)?`).FindStringSubmatch(input)
+	if explain != nil {
+		input = input[len(explain[0]):]
+	}
+
+	ctes, rest, err := parseWith(input)
+	if err != nil {
+		return nil, err
+	}
+
+	operands, ops, err := splitSetOperations(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := parseOperand(operands[0])
+	if err != nil {
+		return nil, err
+	}
+	query.With = append(ctes, query.With...)
+
+	for i, op := range ops {
+		operand, err := parseOperand(operands[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s query: %w", op.Operator, err)
+		}
+		op.Query = operand
+		query.SetOps = append(query.SetOps, op)
+	}
+
+	query.Explain = explain != nil
+	query.Analyze = explain != nil && explain[1] != ""
+	return query, nil
+}
+
+// parseOperand parses one operand of a set operation. A parenthesized operand
+// may itself be a full query with WITH or set operations.
+func parseOperand(input string) (*Query, error) {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "(") && matchingParen(input, 0) == len(input)-1 {
+		return Parse(input[1 : len(input)-1])
+	}
+	return parseSelect(input)
+}
+
+// parseWith parses a leading "WITH name AS (query)[, name AS (query)]..."
+// list and returns the CTEs along with the remaining main query.
+func parseWith(input string) ([]CTE, string, error) {
+	withRe := regexp.MustCompile(`(?i)^WITH\s+`)
+	loc := withRe.FindStringIndex(input)
+	if loc == nil {
+		return nil, input, nil
+	}
+
+	headRe := regexp.MustCompile(`(?i)^(\w+)\s+AS\s*\(`)
+	rest := input[loc[1]:]
+	var ctes []CTE
+	for {
+		m := headRe.FindStringSubmatchIndex(rest)
+		if m == nil {
+			return nil, "", fmt.Errorf("invalid WITH clause: expected name AS (query)")
+		}
+		name := strings.ToLower(rest[m[2]:m[3]])
+		open := m[1] - 1
+		end := matchingParen(rest, open)
+		if end == -1 {
+			return nil, "", fmt.Errorf("invalid WITH clause: missing ) after %s", name)
+		}
+
+		cteQuery, err := Parse(rest[open+1 : end])
+		if err != nil {
+			return nil, "", fmt.Errorf("error parsing WITH %s: %w", name, err)
+		}
+		ctes = append(ctes, CTE{Name: name, Query: cteQuery})
+
+		rest = strings.TrimSpace(rest[end+1:])
+		if !strings.HasPrefix(rest, ",") {
+			break
+		}
+		rest = strings.TrimSpace(rest[1:])
+	}
+
+	if rest == "" {
+		return nil, "", fmt.Errorf("invalid WITH clause: missing query after WITH")
+	}
+	return ctes, rest, nil
+}
+
+// splitSetOperations splits input at top-level UNION, INTERSECT and EXCEPT
+// keywords (outside parentheses and quotes) that are followed by a query, so
+// an unquoted value like "name = union" is left alone. The returned
+// operations carry the operator only; there is always one more operand than
+// operation.
+func splitSetOperations(input string) ([]string, []SetOperation, error) {
+	var operands []string
+	var ops []SetOperation
+	depth, start := 0, 0
+	var quote byte
+
+	for i := 0; i < len(input); i++ {
+		ch := input[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		case ch == '\'' || ch == '"':
+			quote = ch
+			continue
+		case ch == '(':
+			depth++
+			continue
+		case ch == ')':
+			depth--
+			continue
+		}
+		if depth != 0 || (i > 0 && !isSpace(input[i-1])) {
+			continue
+		}
+
+		word := wordAt(input, i)
+		op := SetOperator(strings.ToUpper(word))
+		if op != Union && op != Intersect && op != Except {
+			continue
+		}
+
+		end := i + len(word)
+		next := strings.TrimLeft(input[end:], " \t")
+		all := strings.EqualFold(wordAt(next, 0), "ALL")
+		if all {
+			end = len(input) - len(next) + 3
+		}
+		// An unquoted value such as "name = union" is not an operator; a
+		// trailing operator is kept so its missing query is reported
+		tail := strings.TrimSpace(input[end:])
+		if !startsQuery(tail) && (tail != "" || endsWithComparison(input[:i])) {
+			continue
+		}
+
+		operands = append(operands, strings.TrimSpace(input[start:i]))
+		ops = append(ops, SetOperation{Operator: op, All: all})
+		start = end
+		i = end - 1
+	}
+	operands = append(operands, strings.TrimSpace(input[start:]))
+
+	for i, operand := range operands {
+		if operand == "" {
+			if len(ops) == 0 {
+				return nil, nil, fmt.Errorf("invalid syntax: missing FROM keyword")
+			}
+			opIdx := i
+			if opIdx >= len(ops) {
+				opIdx = len(ops) - 1
+			}
+			return nil, nil, fmt.Errorf("invalid %s: missing query", ops[opIdx].Operator)
+		}
+	}
+	return operands, ops, nil
+}
+
+// startsQuery reports whether input begins with a query: a parenthesized
+// one, SELECT, or a field list followed by FROM.
+func startsQuery(input string) bool {
+	tokens := strings.Fields(input)
+	if len(tokens) == 0 {
+		return false
+	}
+	if strings.HasPrefix(input, "(") {
+		return true
+	}
+	switch first := strings.ToUpper(tokens[0]); {
+	case first == "SELECT" || first == "KSELECT" || first == "FROM":
+		return true
+	case isKeyword(first):
+		return false
+	}
+	return findKeywordIndex(input, "FROM") != -1
+}
+
+// endsWithComparison reports whether input ends with a comparison operator,
+// so the word after it is a value.
+func endsWithComparison(input string) bool {
+	input = strings.TrimSpace(input)
+	if strings.HasSuffix(input, "=") || strings.HasSuffix(input, "<") || strings.HasSuffix(input, ">") {
+		return true
+	}
+	tokens := strings.Fields(input)
+	return len(tokens) > 0 && strings.EqualFold(tokens[len(tokens)-1], "LIKE")
+}
+
+// wordAt returns the run of letters starting at input[i].
+func wordAt(input string, i int) string {
+	end := i
+	for end < len(input) && (input[end] >= 'A' && input[end] <= 'Z' || input[end] >= 'a' && input[end] <= 'z') {
+		end++
+	}
+	if end < len(input) && !isSpace(input[end]) && input[end] != '(' {
+		return ""
+	}
+	return input[i:end]
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+// matchingParen returns the index of the parenthesis closing the one at
+// input[open], skipping quoted text, or -1 if it is unbalanced.
+func matchingParen(input string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(input); i++ {
+		ch := input[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseSelect parses a single SELECT query without WITH or set operations.
+func parseSelect(input string) (*Query, error) {
 	query := &Query{
 		Labels: make(map[string]string),
 	}
@@ -190,6 +484,12 @@ func setDefaultAlias(agg *AggregateFunc) {
 }
 
 func parseFromAndClauses(query *Query, rest string) error {
+	// FROM logs(pod query)
+	rest, err := parseLogsOf(query, rest)
+	if err != nil {
+		return err
+	}
+
 	// Extract resource name (and optional alias) before any keyword
 	tokens := strings.Fields(rest)
 	if len(tokens) == 0 {
@@ -199,6 +499,20 @@ func parseFromAndClauses(query *Query, rest string) error {
 	query.Resource = strings.ToLower(tokens[0])
 	consumed := 1
 
+	// FROM DESCENDANTS OF resource/name
+	if strings.ToUpper(tokens[0]) == "DESCENDANTS" && len(tokens) > 1 && strings.ToUpper(tokens[1]) == "OF" {
+		if len(tokens) < 3 {
+			return fmt.Errorf("missing object after DESCENDANTS OF (expected resource/name)")
+		}
+		resource, name, ok := strings.Cut(tokens[2], "/")
+		if !ok || resource == "" || name == "" {
+			return fmt.Errorf("invalid object %q after DESCENDANTS OF (expected resource/name)", tokens[2])
+		}
+		query.Resource = "descendants"
+		query.DescendantsOf = &ObjectRef{Resource: strings.ToLower(resource), Name: name}
+		consumed = 3
+	}
+
 	// Check for alias (next token that isn't a keyword)
 	if consumed < len(tokens) && !isKeyword(tokens[consumed]) {
 		query.ResourceAlias = tokens[consumed]
@@ -209,7 +523,7 @@ func parseFromAndClauses(query *Query, rest string) error {
 	remaining := strings.TrimSpace(strings.Join(tokens[consumed:], " "))
 
 	// Parse JOIN clauses
-	remaining, err := parseJoins(query, remaining)
+	remaining, err = parseJoins(query, remaining)
 	if err != nil {
 		return err
 	}
@@ -241,12 +555,72 @@ func parseFromAndClauses(query *Query, rest string) error {
 	// Parse LIMIT / OFFSET
 	parseLimitOffset(query, remaining)
 
+	bareCountToAggregate(query)
+
 	return nil
 }
 
+// parseLogsOf parses a leading "logs(query)" source. The query selects the
+// pods to read; its FROM may be omitted, so logs(pod WHERE ...) works. It
+// returns rest with the parenthesized query replaced by the bare name.
+func parseLogsOf(query *Query, rest string) (string, error) {
+	loc := regexp.MustCompile(`(?i)^logs\s*\(`).FindStringIndex(rest)
+	if loc == nil {
+		return rest, nil
+	}
+	open := loc[1] - 1
+	end := matchingParen(rest, open)
+	if end == -1 {
+		return "", fmt.Errorf("invalid logs(): missing )")
+	}
+
+	inner := strings.TrimSpace(rest[open+1 : end])
+	if inner == "" {
+		return "", fmt.Errorf("invalid logs(): expected a pod query")
+	}
+	if findKeywordIndex(inner, "FROM") == -1 {
+		inner = "FROM " + inner
+	}
+	pods, err := Parse(inner)
+	if err != nil {
+		return "", fmt.Errorf("error parsing logs() query: %w", err)
+	}
+	query.LogsOf = pods
+	return "logs " + rest[end+1:], nil
+}
+
+// bareCountToAggregate treats a plain "count" column in a GROUP BY query of
+// logs as COUNT(*), so "pod, count FROM logs ... GROUP BY pod" reads as it
+// would in a report. Log lines have no count field; other resources, such as
+// events, may, and keep it a field. Grouping by count keeps it a field too.
+func bareCountToAggregate(query *Query) {
+	if len(query.GroupBy) == 0 || !isLogsResource(query.Resource) {
+		return
+	}
+	for _, gb := range query.GroupBy {
+		if strings.EqualFold(gb, "count") {
+			return
+		}
+	}
+	var fields []string
+	for _, f := range query.Fields {
+		if strings.EqualFold(f, "count") {
+			query.Aggregates = append(query.Aggregates, AggregateFunc{Function: "COUNT", Field: "*", Alias: "count"})
+			continue
+		}
+		fields = append(fields, f)
+	}
+	query.Fields = fields
+}
+
+// isLogsResource reports whether resource names the logs resource.
+func isLogsResource(resource string) bool {
+	return strings.EqualFold(resource, "logs") || strings.EqualFold(resource, "log")
+}
+
 func parseJoins(query *Query, input string) (string, error) {
 	// Phase 1: match JOIN header (type + resource + optional alias + ON keyword)
-	headerRe := regexp.MustCompile(`(?i)(INNER|LEFT|RIGHT)\s+JOIN\s+(\w+)(?:\s+(\w+))?\s+ON\s+`)
+	headerRe := regexp.MustCompile(`(?i)(?:(INNER|LEFT|RIGHT)\s+)?JOIN\s+(\w+)(?:\s+(\w+))?\s+ON\s+`)
 	remaining := input
 
 	for {
@@ -255,7 +629,11 @@ func parseJoins(query *Query, input string) (string, error) {
 			break
 		}
 
-		joinType := strings.ToUpper(remaining[loc[2]:loc[3]])
+		// Bare JOIN means INNER JOIN
+		joinType := "INNER"
+		if loc[2] != -1 {
+			joinType = strings.ToUpper(remaining[loc[2]:loc[3]])
+		}
 		resource := remaining[loc[4]:loc[5]]
 		alias := ""
 		if loc[6] != -1 {
@@ -328,6 +706,7 @@ func parseWhere(query *Query, input string) (string, error) {
 		}
 		query.Conditions = conditions
 		extractNamespace(query, conditions)
+		extractJoinNamespaces(query, conditions)
 
 		// Remove WHERE clause from input
 		whereIdx := findKeywordIndex(input, "WHERE")
@@ -459,7 +838,7 @@ func parseOrderBy(orderBy string) []OrderByField {
 
 func extractNamespace(query *Query, conditions *ConditionGroup) {
 	for _, cond := range conditions.Conditions {
-		if (cond.Field == "namespace" || cond.Field == "ns") && cond.Operator == OpEqual {
+		if (cond.Field == "namespace" || cond.Field == "ns") && cond.Operator == OpEqual && cond.Param == "" {
 			query.Namespace = cond.Value
 			return
 		}
@@ -470,6 +849,36 @@ func extractNamespace(query *Query, conditions *ConditionGroup) {
 	}
 }
 
+// extractJoinNamespaces scopes JOIN sides using top-level AND conditions of the
+// form "prefix.namespace = value". A joined side gets its own namespace; the
+// primary side's prefixed form sets the query namespace if none was found.
+func extractJoinNamespaces(query *Query, conditions *ConditionGroup) {
+	if len(query.Joins) == 0 || conditions.LogicalOperator != LogicalAnd {
+		return
+	}
+
+	for _, cond := range conditions.Conditions {
+		if cond.Operator != OpEqual || cond.Param != "" {
+			continue
+		}
+		prefix, field, ok := strings.Cut(cond.Field, ".")
+		if !ok || (field != "namespace" && field != "ns") {
+			continue
+		}
+		if prefix == query.Prefix() {
+			if query.Namespace == "" {
+				query.Namespace = cond.Value
+			}
+			continue
+		}
+		for i := range query.Joins {
+			if query.Joins[i].Prefix() == prefix {
+				query.Joins[i].Namespace = cond.Value
+			}
+		}
+	}
+}
+
 func findKeywordIndex(input string, keyword string) int {
 	upper := strings.ToUpper(input)
 	kw := strings.ToUpper(keyword)
@@ -496,6 +905,7 @@ func isKeyword(token string) bool {
 		"WHERE", "ORDER", "BY", "LIMIT", "OFFSET",
 		"GROUP", "HAVING", "INNER", "LEFT", "RIGHT",
 		"JOIN", "ON", "AND", "OR", "AS",
+		"UNION", "INTERSECT", "EXCEPT",
 	}
 	upper := strings.ToUpper(token)
 	for _, kw := range keywords {
@@ -484,3 +484,210 @@ func TestParseJoinWithWhere(t *testing.T) {
 		t.Errorf("Expected LIMIT 5, got %d", query.Limit)
 	}
 }
+
+func TestParseJoinSideNamespaces(t *testing.T) {
+	query, err := Parse("p.name, svc.name FROM pod p INNER JOIN service svc ON p.labels.app = svc.selector.app WHERE p.namespace = apps AND svc.ns = shared")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if query.Namespace != "apps" {
+		t.Errorf("Expected primary namespace 'apps', got '%s'", query.Namespace)
+	}
+	if query.Joins[0].Namespace != "shared" {
+		t.Errorf("Expected join namespace 'shared', got '%s'", query.Joins[0].Namespace)
+	}
+
+	// OR conditions must not scope the fetch
+	query, err = Parse("p.name FROM pod p INNER JOIN service svc ON p.name = svc.name WHERE svc.namespace = a OR svc.namespace = b")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if query.Joins[0].Namespace != "" {
+		t.Errorf("Expected no join namespace for OR conditions, got '%s'", query.Joins[0].Namespace)
+	}
+}
+
+func TestParseDescendantsOf(t *testing.T) {
+	query, err := Parse("kind,name FROM DESCENDANTS OF Deployment/web WHERE namespace=prod")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if query.Resource != "descendants" {
+		t.Errorf("Expected resource 'descendants', got '%s'", query.Resource)
+	}
+	if query.DescendantsOf == nil || query.DescendantsOf.Resource != "deployment" || query.DescendantsOf.Name != "web" {
+		t.Errorf("Expected DescendantsOf deployment/web, got %+v", query.DescendantsOf)
+	}
+	if query.Namespace != "prod" {
+		t.Errorf("Expected namespace 'prod', got '%s'", query.Namespace)
+	}
+
+	if _, err := Parse("name FROM DESCENDANTS OF web"); err == nil {
+		t.Error("Expected error for object without resource/ prefix")
+	}
+}
+
+func TestParseWithCTE(t *testing.T) {
+	query, err := Parse("WITH crashing AS (SELECT name, node FROM pod WHERE restarts > 5), big AS (name FROM node) SELECT c.name, node.name FROM crashing c JOIN node ON c.node = node.name")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(query.With) != 2 {
+		t.Fatalf("Expected 2 CTEs, got %d", len(query.With))
+	}
+	if query.With[0].Name != "crashing" || query.With[0].Query.Resource != "pod" {
+		t.Errorf("Expected crashing AS pod query, got %s AS %s", query.With[0].Name, query.With[0].Query.Resource)
+	}
+	if query.With[0].Query.Conditions == nil {
+		t.Error("Expected WHERE in CTE query")
+	}
+	if query.Resource != "crashing" || query.ResourceAlias != "c" {
+		t.Errorf("Expected FROM crashing c, got %s %s", query.Resource, query.ResourceAlias)
+	}
+	if len(query.Joins) != 1 || query.Joins[0].Type != InnerJoin {
+		t.Fatalf("Expected bare JOIN to parse as INNER JOIN, got %+v", query.Joins)
+	}
+
+	if _, err := Parse("WITH broken AS (name FROM pod SELECT name FROM broken"); err == nil {
+		t.Error("Expected error for unterminated WITH query")
+	}
+}
+
+func TestParseSetOperations(t *testing.T) {
+	query, err := Parse("name, namespace FROM deployment UNION ALL name, namespace FROM statefulset WHERE name = 'except-me' EXCEPT (name, namespace FROM daemonset)")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if query.Resource != "deployment" {
+		t.Errorf("Expected head resource 'deployment', got '%s'", query.Resource)
+	}
+	if len(query.SetOps) != 2 {
+		t.Fatalf("Expected 2 set operations, got %d", len(query.SetOps))
+	}
+	if query.SetOps[0].Operator != Union || !query.SetOps[0].All || query.SetOps[0].Query.Resource != "statefulset" {
+		t.Errorf("Expected UNION ALL statefulset, got %+v", query.SetOps[0])
+	}
+	if query.SetOps[0].Query.Conditions == nil {
+		t.Error("Expected WHERE on UNION operand")
+	}
+	if query.SetOps[1].Operator != Except || query.SetOps[1].All || query.SetOps[1].Query.Resource != "daemonset" {
+		t.Errorf("Expected EXCEPT daemonset, got %+v", query.SetOps[1])
+	}
+
+	if _, err := Parse("name FROM pod UNION"); err == nil {
+		t.Error("Expected error for UNION without right operand")
+	}
+}
+
+func TestParseSetOperatorAsValue(t *testing.T) {
+	tests := []struct {
+		input  string
+		values []string
+		ops    int
+	}{
+		{"name FROM pod WHERE name = union", []string{"union"}, 0},
+		{"name FROM pod WHERE name = except AND namespace != intersect LIMIT 5", []string{"except", "intersect"}, 0},
+		{"name FROM pod WHERE name LIKE union ORDER BY name", []string{"union"}, 0},
+		{"name FROM pod WHERE name = union UNION name FROM service", []string{"union"}, 1},
+	}
+	for _, tt := range tests {
+		query, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("%s: Parse failed: %v", tt.input, err)
+			continue
+		}
+		if len(query.SetOps) != tt.ops {
+			t.Errorf("%s: expected %d set operations, got %d", tt.input, tt.ops, len(query.SetOps))
+		}
+		conds := query.Conditions.Conditions
+		if len(conds) != len(tt.values) {
+			t.Errorf("%s: expected %d conditions, got %+v", tt.input, len(tt.values), conds)
+			continue
+		}
+		for i, want := range tt.values {
+			if conds[i].Value != want {
+				t.Errorf("%s: condition %d: expected value %q, got %v", tt.input, i, want, conds[i].Value)
+			}
+		}
+	}
+}
+
+func TestParseLogsOf(t *testing.T) {
+	query, err := Parse("pod, count FROM logs(pod WHERE labels.app = web AND namespace = prod) WHERE line LIKE '%panic%' AND ts > now()-1h GROUP BY pod")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if query.Resource != "logs" || query.LogsOf == nil {
+		t.Fatalf("Expected FROM logs(...), got %s %+v", query.Resource, query.LogsOf)
+	}
+	if query.LogsOf.Resource != "pod" || query.LogsOf.Namespace != "prod" || len(query.LogsOf.Conditions.Conditions) != 2 {
+		t.Errorf("Expected inner pod query in prod with 2 conditions, got %+v", query.LogsOf)
+	}
+	if query.Namespace != "" || len(query.Conditions.Conditions) != 2 || query.Conditions.Conditions[1].Value != "now()-1h" {
+		t.Errorf("Expected outer WHERE on line and ts only, got %+v", query.Conditions)
+	}
+	if len(query.Fields) != 1 || len(query.Aggregates) != 1 || query.Aggregates[0].Alias != "count" {
+		t.Errorf("Expected bare count to be COUNT(*), got fields %v aggregates %v", query.Fields, query.Aggregates)
+	}
+
+	query, err = Parse("name FROM logs(name FROM pod WHERE restarts > 0) LIMIT 5")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if query.LogsOf == nil || query.LogsOf.Resource != "pod" || query.Limit != 5 {
+		t.Errorf("Expected full inner query and LIMIT 5, got %+v", query)
+	}
+
+	// Without GROUP BY, count stays a field (e.g. event count)
+	query, err = Parse("reason, count FROM event")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(query.Fields) != 2 || len(query.Aggregates) != 0 {
+		t.Errorf("Expected count field, got fields %v aggregates %v", query.Fields, query.Aggregates)
+	}
+	// Nor with GROUP BY on resources other than logs
+	query, err = Parse("reason, count FROM event GROUP BY reason")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(query.Fields) != 2 || len(query.Aggregates) != 0 {
+		t.Errorf("Expected count field of event, got fields %v aggregates %v", query.Fields, query.Aggregates)
+	}
+	query, err = Parse("pod, count FROM log GROUP BY pod")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(query.Aggregates) != 1 {
+		t.Errorf("Expected bare count of FROM log to be COUNT(*), got aggregates %v", query.Aggregates)
+	}
+
+	for _, input := range []string{"line FROM logs(pod", "line FROM logs()"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestParseExplain(t *testing.T) {
+	tests := []struct {
+		input            string
+		explain, analyze bool
+	}{
+		{"name FROM pod", false, false},
+		{"EXPLAIN name FROM pod WHERE status = Running", true, false},
+		{"explain analyze WITH web AS (name FROM pod) name FROM web UNION name FROM service", true, true},
+	}
+	for _, tt := range tests {
+		query, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+		}
+		if query.Explain != tt.explain || query.Analyze != tt.analyze {
+			t.Errorf("Parse(%q): Explain = %v, Analyze = %v, want %v, %v", tt.input, query.Explain, query.Analyze, tt.explain, tt.analyze)
+		}
+		if query.Resource == "" || (len(query.SetOps) > 0 && query.SetOps[0].Query.Explain) {
+			t.Errorf("Parse(%q): unexpected query %+v", tt.input, query)
+		}
+	}
+}
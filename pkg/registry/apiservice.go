@@ -0,0 +1,67 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "apiservice",
+		Aliases: []string{"apiservices"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "apiregistration.k8s.io",
+			Version:  "v1",
+			Resource: "apiservices",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "service", "available", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "APIService name",
+				Type:        "string",
+			},
+			"service": {
+				Name:        "service",
+				Expr:        ".spec.service ? .spec.service.namespace + '/' + .spec.service.name : 'Local'",
+				Description: "Backing service, or Local",
+				Type:        "string",
+			},
+			"group": {
+				Name:        "group",
+				JSONPath:    "{.spec.group}",
+				Description: "API group",
+				Type:        "string",
+			},
+			"version": {
+				Name:        "version",
+				JSONPath:    "{.spec.version}",
+				Description: "API version",
+				Type:        "string",
+			},
+			"available": {
+				Name:        "available",
+				Expr:        "condition(.status.conditions, 'Available')",
+				Description: "Available condition status",
+				Type:        "string",
+			},
+			"reason": {
+				Name:        "reason",
+				Expr:        "condition(.status.conditions, 'Available', 'reason')",
+				Description: "Reason when not available",
+				Type:        "string",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
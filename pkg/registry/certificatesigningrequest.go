@@ -0,0 +1,68 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "certificatesigningrequest",
+		Aliases: []string{"certificatesigningrequests", "csr"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "certificates.k8s.io",
+			Version:  "v1",
+			Resource: "certificatesigningrequests",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "signer", "requestor", "condition", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "CertificateSigningRequest name",
+				Type:        "string",
+			},
+			"signer": {
+				Name:        "signer",
+				JSONPath:    "{.spec.signerName}",
+				Description: "Signer name",
+				Type:        "string",
+			},
+			"requestor": {
+				Name:        "requestor",
+				JSONPath:    "{.spec.username}",
+				Description: "User that created the request",
+				Type:        "string",
+			},
+			"requested-duration": {
+				Name:        "requested-duration",
+				JSONPath:    "{.spec.expirationSeconds}",
+				Description: "Requested validity in seconds",
+				Type:        "int",
+			},
+			"usages": {
+				Name:        "usages",
+				JSONPath:    "{.spec.usages}",
+				Description: "Key usages",
+				Type:        "list",
+			},
+			"condition": {
+				Name:        "condition",
+				Aliases:     []string{"status"},
+				Expr:        "len(.status.conditions) > 0 ? join(.status.conditions[*].type, ',') + (.status.certificate ? ',Issued' : '') : 'Pending'",
+				Description: "Approved, Denied or Pending, plus Issued",
+				Type:        "string",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
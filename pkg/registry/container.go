@@ -0,0 +1,159 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	// Rows are the containers of listed pods. The executor gives each
+	// container an object of its own: metadata (container name, plus the
+	// pod's namespace, creationTimestamp and labels), pod (name, node), init,
+	// spec (the container), status (its containerStatus) and metrics (its
+	// usage from the pod's metrics).
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "container",
+		Aliases: []string{"containers"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "pods",
+		},
+		Namespaced:    true,
+		Metrics:       "pods",
+		Scanner:       ScanContainers,
+		DefaultFields: []string{"pod", "name", "image", "ready", "restarts", "state"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "Container name",
+				Type:        "string",
+			},
+			"namespace": {
+				Name:        "namespace",
+				Aliases:     []string{"ns"},
+				JSONPath:    "{.metadata.namespace}",
+				Description: "Namespace",
+				Type:        "string",
+			},
+			"pod": {
+				Name:        "pod",
+				JSONPath:    "{.pod.name}",
+				Description: "Pod name",
+				Type:        "string",
+			},
+			"node": {
+				Name:        "node",
+				JSONPath:    "{.pod.node}",
+				Description: "Node of the pod",
+				Type:        "string",
+			},
+			"init": {
+				Name:        "init",
+				JSONPath:    "{.init}",
+				Description: "Whether this is an init container",
+				Type:        "string",
+			},
+			"image": {
+				Name:        "image",
+				JSONPath:    "{.spec.image}",
+				Description: "Container image",
+				Type:        "string",
+			},
+			"ready": {
+				Name:        "ready",
+				JSONPath:    "{.status.ready}",
+				Description: "Whether the container is ready",
+				Type:        "string",
+			},
+			"restarts": {
+				Name:        "restarts",
+				JSONPath:    "{.status.restartCount}",
+				Description: "Restart count",
+				Type:        "int",
+			},
+			"state": {
+				Name:        "state",
+				Expr:        ".status.state.waiting != null ? coalesce(.status.state.waiting.reason, 'Waiting') : .status.state.terminated != null ? coalesce(.status.state.terminated.reason, 'Terminated') : .status.state.running != null ? 'Running' : null",
+				Description: "Running, or the waiting or terminated reason",
+				Type:        "string",
+			},
+			"cpu.req-m": {
+				Name:        "cpu.req-m",
+				Expr:        "cpu(.spec.resources.requests.cpu)",
+				Description: "CPU requests in millicores",
+				Type:        "int",
+			},
+			"cpu.limit-m": {
+				Name:        "cpu.limit-m",
+				Expr:        "cpu(.spec.resources.limits.cpu)",
+				Description: "CPU limits in millicores",
+				Type:        "int",
+			},
+			"mem.req-mi": {
+				Name:        "mem.req-mi",
+				Expr:        "memory(.spec.resources.requests.memory)",
+				Description: "Memory requests in MiB",
+				Type:        "int",
+			},
+			"mem.limit-mi": {
+				Name:        "mem.limit-mi",
+				Expr:        "memory(.spec.resources.limits.memory)",
+				Description: "Memory limits in MiB",
+				Type:        "int",
+			},
+			"cpu.usage": {
+				Name:        "cpu.usage",
+				Expr:        "cpu(.metrics.usage.cpu)",
+				Description: "CPU usage in millicores (metrics API)",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"mem.usage": {
+				Name:        "mem.usage",
+				Expr:        "memory(.metrics.usage.memory)",
+				Description: "Memory usage in MiB (metrics API)",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"cpu.usage-pct-of-req": {
+				Name:        "cpu.usage-pct-of-req",
+				Expr:        "round(cpu(.metrics.usage.cpu) * 100 / cpu(.spec.resources.requests.cpu))",
+				Description: "CPU usage as a percentage of requests",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"mem.usage-pct-of-req": {
+				Name:        "mem.usage-pct-of-req",
+				Expr:        "round(memory(.metrics.usage.memory) * 100 / memory(.spec.resources.requests.memory))",
+				Description: "Memory usage as a percentage of requests",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"cpu.usage-pct-of-limit": {
+				Name:        "cpu.usage-pct-of-limit",
+				Expr:        "round(cpu(.metrics.usage.cpu) * 100 / cpu(.spec.resources.limits.cpu))",
+				Description: "CPU usage as a percentage of limits",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"mem.usage-pct-of-limit": {
+				Name:        "mem.usage-pct-of-limit",
+				Expr:        "round(memory(.metrics.usage.memory) * 100 / memory(.spec.resources.limits.memory))",
+				Description: "Memory usage as a percentage of limits",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age of the pod",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Pod labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -0,0 +1,324 @@
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxSchemaDepth bounds how deep generated fields reach into a CRD schema.
+const maxSchemaDepth = 4
+
+// GeneratePlugin renders the plugin YAML for a CustomResourceDefinition, so
+// it can be saved to a plugin directory and edited by hand.
+// Printer columns that cannot be converted are left out, with a warning
+// comment at the top.
+func GeneratePlugin(crd map[string]interface{}) ([]byte, error) {
+	plugin, warnings, err := pluginFromCRD(crd)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by kselect plugin generate from CRD %s\n", nestedString(crd, "metadata", "name"))
+	for _, w := range warnings {
+		fmt.Fprintf(&buf, "# Warning: %s\n", w)
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(plugin); err != nil {
+		return nil, fmt.Errorf("failed to encode plugin: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode plugin: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DefinitionFromCRD builds a resource definition straight from a
+// CustomResourceDefinition, as GeneratePlugin followed by loading would.
+func DefinitionFromCRD(crd map[string]interface{}) (*ResourceDefinition, error) {
+	plugin, _, err := pluginFromCRD(crd)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.toResourceDefinition(), nil
+}
+
+// pluginFromCRD converts a CRD: its served storage version gives the GVR,
+// additionalPrinterColumns become the default fields and the OpenAPI v3
+// schema of spec and status adds one field per property path. It returns a
+// warning for each printer column it leaves out.
+func pluginFromCRD(crd map[string]interface{}) (*pluginDefinition, []string, error) {
+	if kind := nestedString(crd, "kind"); kind != "CustomResourceDefinition" {
+		return nil, nil, fmt.Errorf("expected a CustomResourceDefinition, got %q", kind)
+	}
+
+	group := nestedString(crd, "spec", "group")
+	plural := nestedString(crd, "spec", "names", "plural")
+	if group == "" || plural == "" {
+		return nil, nil, fmt.Errorf("CRD %s has no spec.group or spec.names.plural", nestedString(crd, "metadata", "name"))
+	}
+	kind := nestedString(crd, "spec", "names", "kind")
+	singular := nestedString(crd, "spec", "names", "singular")
+	if singular == "" {
+		singular = strings.ToLower(kind)
+	}
+
+	version := crdVersion(crd)
+	if version == nil {
+		return nil, nil, fmt.Errorf("CRD %s has no served version", nestedString(crd, "metadata", "name"))
+	}
+
+	namespaced := nestedString(crd, "spec", "scope") != "Cluster"
+	plugin := &pluginDefinition{
+		Name:       singular,
+		Aliases:    append([]string{plural}, nestedStrings(crd, "spec", "names", "shortNames")...),
+		Group:      group,
+		Version:    nestedString(version, "name"),
+		Resource:   plural,
+		Namespaced: &namespaced,
+		Fields: map[string]pluginFieldDef{
+			"name":   {JSONPath: "{.metadata.name}", Type: "string", Description: kind + " name"},
+			"labels": {JSONPath: "{.metadata.labels}", Type: "map", Description: "Labels"},
+			"age":    {JSONPath: "{.metadata.creationTimestamp}", Type: "time", Description: "Age"},
+		},
+	}
+	if namespaced {
+		plugin.Fields["namespace"] = pluginFieldDef{JSONPath: "{.metadata.namespace}", Type: "string", Description: "Namespace", Aliases: []string{"ns"}}
+	}
+
+	// Schema properties, named by their path (e.g. "spec.secretName")
+	schema, _, _ := nestedMap(version, "schema", "openAPIV3Schema")
+	for _, root := range []string{"spec", "status"} {
+		if prop, ok, _ := nestedMap(schema, "properties", root); ok {
+			addSchemaFields(plugin.Fields, root, prop, 1)
+		}
+	}
+
+	// Printer columns, named like kubectl shows them (e.g. "not-after")
+	plugin.DefaultFields = []string{"name"}
+	var warnings []string
+	columns, _, _ := nestedSlice(version, "additionalPrinterColumns")
+	for _, c := range columns {
+		col, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := fieldNameFromColumn(nestedString(col, "name"))
+		path := nestedString(col, "jsonPath")
+		if name == "" || path == "" {
+			continue
+		}
+
+		field := pluginFieldDef{
+			Type:        columnType(nestedString(col, "type")),
+			Description: nestedString(col, "description"),
+		}
+		if expr, ok := conditionExpr(path); ok {
+			field.Expr = expr
+		} else if err := checkPathSubset(path); err != nil {
+			warnings = append(warnings, fmt.Sprintf("column %q skipped: jsonPath %s: %v; add a field with expr: to show it", nestedString(col, "name"), path, err))
+			continue
+		} else {
+			field.JSONPath = "{" + path + "}"
+		}
+		if existing, ok := plugin.Fields[strings.TrimPrefix(path, ".")]; ok && field.Description == "" {
+			field.Description = existing.Description
+		}
+		if name != "age" {
+			plugin.Fields[name] = field
+		}
+
+		if priority, _, _ := nestedInt64(col, "priority"); priority == 0 {
+			plugin.DefaultFields = append(plugin.DefaultFields, name)
+		}
+	}
+	if len(columns) == 0 {
+		plugin.DefaultFields = append(plugin.DefaultFields, "age")
+	}
+
+	return plugin, warnings, nil
+}
+
+// crdVersion returns the storage version, falling back to the first served one.
+func crdVersion(crd map[string]interface{}) map[string]interface{} {
+	versions, _, _ := nestedSlice(crd, "spec", "versions")
+	var served map[string]interface{}
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ok, _, _ := nestedBool(version, "served"); !ok {
+			continue
+		}
+		if storage, _, _ := nestedBool(version, "storage"); storage {
+			return version
+		}
+		if served == nil {
+			served = version
+		}
+	}
+	return served
+}
+
+// addSchemaFields adds a field for each property below path. Objects with
+// declared properties are descended into; everything else becomes one field.
+func addSchemaFields(fields map[string]pluginFieldDef, path string, prop map[string]interface{}, depth int) {
+	props, hasProps, _ := nestedMap(prop, "properties")
+	if hasProps && depth < maxSchemaDepth {
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if child, ok := props[name].(map[string]interface{}); ok {
+				addSchemaFields(fields, path+"."+name, child, depth+1)
+			}
+		}
+		return
+	}
+
+	fields[path] = pluginFieldDef{
+		JSONPath:    "{." + path + "}",
+		Type:        schemaType(prop),
+		Description: firstLine(nestedString(prop, "description")),
+	}
+}
+
+// schemaType maps an OpenAPI property to a registry field type.
+func schemaType(prop map[string]interface{}) string {
+	switch nestedString(prop, "type") {
+	case "integer":
+		return "int"
+	case "array":
+		return "list"
+	case "object":
+		return "map"
+	case "string":
+		if nestedString(prop, "format") == "date-time" {
+			return "time"
+		}
+	}
+	return "string"
+}
+
+// columnType maps an additionalPrinterColumns type to a registry field type.
+// Numbers and booleans are left untyped, so their values keep their own type.
+func columnType(t string) string {
+	switch t {
+	case "integer":
+		return "int"
+	case "date":
+		return "time"
+	case "string":
+		return "string"
+	}
+	return ""
+}
+
+// conditionRe matches the printer column path of a condition, e.g.
+// .status.conditions[?(@.type=="Ready")].status.
+var conditionRe = regexp.MustCompile(`^(\.[\w.]+)\[\?\(@\.type\s*==\s*["']([^"']+)["']\)\]\.(\w+)$`)
+
+// conditionExpr returns the expr reading a condition printer column path.
+func conditionExpr(path string) (string, bool) {
+	m := conditionRe.FindStringSubmatch(path)
+	if m == nil {
+		return "", false
+	}
+	if m[3] == "status" {
+		return fmt.Sprintf("condition(%s, '%s')", m[1], m[2]), true
+	}
+	return fmt.Sprintf("condition(%s, '%s', '%s')", m[1], m[2], m[3]), true
+}
+
+// fieldNameFromColumn turns a printer column name into a field name in the
+// registry's style: "Not After" → "not-after", "ReadyReplicas" → "ready-replicas".
+func fieldNameFromColumn(column string) string {
+	var b strings.Builder
+	prevLower := false
+	for _, r := range strings.TrimSpace(column) {
+		switch {
+		case unicode.IsUpper(r):
+			if prevLower {
+				b.WriteByte('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			prevLower = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			prevLower = true
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+			prevLower = false
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+// The nested* helpers read CRD values without copying them, so they accept
+// objects decoded from YAML as well as from JSON. Missing or mistyped values
+// read as zero values.
+
+func nestedString(obj map[string]interface{}, fields ...string) string {
+	s, _, _ := unstructured.NestedString(obj, fields...)
+	return s
+}
+
+func nestedStrings(obj map[string]interface{}, fields ...string) []string {
+	values, _, _ := nestedSlice(obj, fields...)
+	var result []string
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func nestedMap(obj map[string]interface{}, fields ...string) (map[string]interface{}, bool, error) {
+	val, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	m, ok := val.(map[string]interface{})
+	return m, found && ok, err
+}
+
+func nestedSlice(obj map[string]interface{}, fields ...string) ([]interface{}, bool, error) {
+	val, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	s, ok := val.([]interface{})
+	return s, found && ok, err
+}
+
+func nestedBool(obj map[string]interface{}, fields ...string) (bool, bool, error) {
+	val, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	b, ok := val.(bool)
+	return b, found && ok, err
+}
+
+func nestedInt64(obj map[string]interface{}, fields ...string) (int64, bool, error) {
+	val, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	switch n := val.(type) {
+	case int64:
+		return n, found, err
+	case int:
+		return int64(n), found, err
+	case float64:
+		return int64(n), found, err
+	}
+	return 0, false, err
+}
@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func loadTestCRD(t *testing.T) map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "certificate-crd.yaml"))
+	if err != nil {
+		t.Fatalf("failed to read CRD: %v", err)
+	}
+	var crd map[string]interface{}
+	if err := yaml.Unmarshal(data, &crd); err != nil {
+		t.Fatalf("failed to parse CRD: %v", err)
+	}
+	return crd
+}
+
+func TestDefinitionFromCRD(t *testing.T) {
+	def, err := DefinitionFromCRD(loadTestCRD(t))
+	if err != nil {
+		t.Fatalf("DefinitionFromCRD failed: %v", err)
+	}
+
+	if def.Name != "certificate" || def.GroupVersionResource.Group != "cert-manager.io" ||
+		def.GroupVersionResource.Version != "v1" || def.GroupVersionResource.Resource != "certificates" {
+		t.Errorf("Unexpected resource %s %v", def.Name, def.GroupVersionResource)
+	}
+	if !def.Namespaced {
+		t.Error("Expected namespaced resource")
+	}
+
+	// Printer columns without priority become default fields, in order
+	expectedDefaults := []string{"name", "ready", "secret", "not-after", "age"}
+	if len(def.DefaultFields) != len(expectedDefaults) {
+		t.Fatalf("Expected default fields %v, got %v", expectedDefaults, def.DefaultFields)
+	}
+	for i, f := range expectedDefaults {
+		if def.DefaultFields[i] != f {
+			t.Errorf("Expected default field %d to be %q, got %q", i, f, def.DefaultFields[i])
+		}
+	}
+
+	tests := []struct {
+		field, jsonPath, typ, description string
+	}{
+		{"not-after", "{.status.notAfter}", "time", "The expiration time of the certificate."},
+		{"secret", "{.spec.secretName}", "string", "Name of the Secret resource that will be automatically created."},
+		{"issuer", "{.spec.issuerRef.name}", "string", "Name of the issuer being referred to."},
+		{"spec.dnsNames", "{.spec.dnsNames}", "list", ""},
+		{"spec.issuerRef.kind", "{.spec.issuerRef.kind}", "string", ""},
+		{"spec.secretTemplate.labels", "{.spec.secretTemplate.labels}", "map", ""},
+		{"status.revision", "{.status.revision}", "int", ""},
+		{"age", "{.metadata.creationTimestamp}", "time", "Age"},
+		{"revision", "{.status.revision}", "int", ""},
+		{"renewal", "{.status.renewalRatio}", "", ""},
+	}
+	for _, tt := range tests {
+		f, ok := def.Fields[tt.field]
+		if !ok {
+			t.Errorf("Expected field %q", tt.field)
+			continue
+		}
+		if f.JSONPath != tt.jsonPath || f.Type != tt.typ || f.Description != tt.description {
+			t.Errorf("Field %q = {%s %s %q}, want {%s %s %q}", tt.field, f.JSONPath, f.Type, f.Description, tt.jsonPath, tt.typ, tt.description)
+		}
+	}
+	if _, ok := def.Fields["namespace"]; !ok {
+		t.Error("Expected namespace field for namespaced CRD")
+	}
+
+	// Condition columns become condition() expressions; other paths the
+	// executor cannot read are left out
+	if f := def.Fields["ready"]; f.Expr != "condition(.status.conditions, 'Ready')" || f.JSONPath != "" {
+		t.Errorf("Expected ready to read the Ready condition, got %+v", f)
+	}
+	if _, ok := def.Fields["hosts"]; ok {
+		t.Error("Expected the hosts column with a slice path to be skipped")
+	}
+}
+
+func TestGeneratePluginLoads(t *testing.T) {
+	data, err := GeneratePlugin(loadTestCRD(t))
+	if err != nil {
+		t.Fatalf("GeneratePlugin failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "certificate.yaml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reg := NewRegistry()
+	if err := loadPlugin(path, reg, nil); len(err) > 0 {
+		t.Fatalf("generated plugin does not load: %v\n%s", err, data)
+	}
+	def, ok := reg.Get("certs")
+	if !ok || def.Name != "certificate" || def.Fields["ready"].Expr != "condition(.status.conditions, 'Ready')" {
+		t.Errorf("Unexpected loaded definition: %+v", def)
+	}
+	if !strings.Contains(string(data), `# Warning: column "Hosts" skipped: jsonPath .spec.dnsNames[0:2]: unsupported syntax`) {
+		t.Errorf("Expected a warning for the skipped Hosts column, got:\n%s", data)
+	}
+}
+
+func TestDefinitionFromCRDRejectsOtherKinds(t *testing.T) {
+	if _, err := DefinitionFromCRD(map[string]interface{}{"kind": "Deployment"}); err == nil {
+		t.Error("Expected error for non-CRD object")
+	}
+}
+
+func TestFieldNameFromColumn(t *testing.T) {
+	tests := map[string]string{
+		"Ready":         "ready",
+		"Not After":     "not-after",
+		"ReadyReplicas": "ready-replicas",
+		"Up-To-Date":    "up-to-date",
+		"IP":            "ip",
+	}
+	for column, want := range tests {
+		if got := fieldNameFromColumn(column); got != want {
+			t.Errorf("fieldNameFromColumn(%q) = %q, want %q", column, got, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "csidriver",
+		Aliases: []string{"csidrivers"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "storage.k8s.io",
+			Version:  "v1",
+			Resource: "csidrivers",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "attach-required", "pod-info-on-mount", "storage-capacity", "modes", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "CSIDriver name",
+				Type:        "string",
+			},
+			"attach-required": {
+				Name:        "attach-required",
+				JSONPath:    "{.spec.attachRequired}",
+				Description: "Whether volumes need an attach operation",
+				Type:        "string",
+			},
+			"pod-info-on-mount": {
+				Name:        "pod-info-on-mount",
+				JSONPath:    "{.spec.podInfoOnMount}",
+				Description: "Whether pod info is passed on mount",
+				Type:        "string",
+			},
+			"storage-capacity": {
+				Name:        "storage-capacity",
+				JSONPath:    "{.spec.storageCapacity}",
+				Description: "Whether the driver reports storage capacity",
+				Type:        "string",
+			},
+			"token-requests": {
+				Name:        "token-requests",
+				JSONPath:    "{.spec.tokenRequests[*].audience}",
+				Description: "Service account token audiences",
+				Type:        "list",
+			},
+			"requires-republish": {
+				Name:        "requires-republish",
+				JSONPath:    "{.spec.requiresRepublish}",
+				Description: "Whether volumes are periodically republished",
+				Type:        "string",
+			},
+			"modes": {
+				Name:        "modes",
+				JSONPath:    "{.spec.volumeLifecycleModes}",
+				Description: "Volume lifecycle modes",
+				Type:        "list",
+			},
+			"fs-group-policy": {
+				Name:        "fs-group-policy",
+				JSONPath:    "{.spec.fsGroupPolicy}",
+				Description: "fsGroup ownership policy",
+				Type:        "string",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "customresourcedefinition",
+		Aliases: []string{"customresourcedefinitions", "crd", "crds"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "apiextensions.k8s.io",
+			Version:  "v1",
+			Resource: "customresourcedefinitions",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "group", "kind", "scope", "versions", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "CustomResourceDefinition name",
+				Type:        "string",
+			},
+			"group": {
+				Name:        "group",
+				JSONPath:    "{.spec.group}",
+				Description: "API group",
+				Type:        "string",
+			},
+			"kind": {
+				Name:        "kind",
+				JSONPath:    "{.spec.names.kind}",
+				Description: "Kind",
+				Type:        "string",
+			},
+			"plural": {
+				Name:        "plural",
+				JSONPath:    "{.spec.names.plural}",
+				Description: "Plural resource name",
+				Type:        "string",
+			},
+			"short-names": {
+				Name:        "short-names",
+				JSONPath:    "{.spec.names.shortNames}",
+				Description: "Short names",
+				Type:        "list",
+			},
+			"scope": {
+				Name:        "scope",
+				JSONPath:    "{.spec.scope}",
+				Description: "Namespaced or Cluster",
+				Type:        "string",
+			},
+			"versions": {
+				Name:        "versions",
+				JSONPath:    "{.spec.versions[*].name}",
+				Description: "Served versions",
+				Type:        "list",
+			},
+			"storage-version": {
+				Name:        "storage-version",
+				JSONPath:    "{.status.storedVersions}",
+				Description: "Versions stored in etcd",
+				Type:        "list",
+			},
+			"established": {
+				Name:        "established",
+				Expr:        "condition(.status.conditions, 'Established')",
+				Description: "Established condition status",
+				Type:        "string",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -101,26 +101,26 @@ func init() {
 			},
 			"cpu.req-m": {
 				Name:        "cpu.req-m",
-				JSONPath:    "{.spec.template.spec.containers[*].resources.requests.cpu}",
-				Description: "CPU requests in millicores",
+				Expr:        "sum(cpu(.spec.template.spec.containers[*].resources.requests.cpu))",
+				Description: "Total CPU requests in millicores",
 				Type:        "int",
 			},
 			"cpu.limit-m": {
 				Name:        "cpu.limit-m",
-				JSONPath:    "{.spec.template.spec.containers[*].resources.limits.cpu}",
-				Description: "CPU limits in millicores",
+				Expr:        "sum(cpu(.spec.template.spec.containers[*].resources.limits.cpu))",
+				Description: "Total CPU limits in millicores",
 				Type:        "int",
 			},
 			"mem.req-mi": {
 				Name:        "mem.req-mi",
-				JSONPath:    "{.spec.template.spec.containers[*].resources.requests.memory}",
-				Description: "Memory requests in MiB",
+				Expr:        "sum(memory(.spec.template.spec.containers[*].resources.requests.memory))",
+				Description: "Total memory requests in MiB",
 				Type:        "int",
 			},
 			"mem.limit-mi": {
 				Name:        "mem.limit-mi",
-				JSONPath:    "{.spec.template.spec.containers[*].resources.limits.memory}",
-				Description: "Memory limits in MiB",
+				Expr:        "sum(memory(.spec.template.spec.containers[*].resources.limits.memory))",
+				Description: "Total memory limits in MiB",
 				Type:        "int",
 			},
 			"age": {
@@ -0,0 +1,86 @@
+package registry
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// NewDiscoveredDefinition builds a definition for a resource found through
+// API discovery rather than registered in code or a plugin. It has the
+// metadata fields every object shares and allows raw paths such as
+// "spec.replicas" for everything else.
+func NewDiscoveredDefinition(name, kind string, gvr schema.GroupVersionResource, namespaced bool) *ResourceDefinition {
+	fields := map[string]FieldDefinition{
+		"name": {
+			Name:        "name",
+			JSONPath:    "{.metadata.name}",
+			Description: kind + " name",
+			Type:        "string",
+		},
+		"kind": {
+			Name:        "kind",
+			JSONPath:    "{.kind}",
+			Description: "Object kind",
+			Type:        "string",
+		},
+		"labels": {
+			Name:        "labels",
+			JSONPath:    "{.metadata.labels}",
+			Description: "Labels",
+			Type:        "map",
+		},
+		"annotations": {
+			Name:        "annotations",
+			JSONPath:    "{.metadata.annotations}",
+			Description: "Annotations",
+			Type:        "map",
+		},
+		"age": {
+			Name:        "age",
+			JSONPath:    "{.metadata.creationTimestamp}",
+			Description: "Age",
+			Type:        "time",
+		},
+		"ownerReferences": {
+			Name:        "ownerReferences",
+			Aliases:     []string{"owners"},
+			JSONPath:    "{.metadata.ownerReferences}",
+			Description: "Owner references",
+			Type:        "list",
+		},
+	}
+	defaults := []string{"name", "age"}
+	if namespaced {
+		fields["namespace"] = FieldDefinition{
+			Name:        "namespace",
+			Aliases:     []string{"ns"},
+			JSONPath:    "{.metadata.namespace}",
+			Description: "Namespace",
+			Type:        "string",
+		}
+		defaults = []string{"name", "namespace", "age"}
+	}
+
+	return &ResourceDefinition{
+		Name:                 name,
+		GroupVersionResource: gvr,
+		Namespaced:           namespaced,
+		DefaultFields:        defaults,
+		Fields:               fields,
+		RawPaths:             true,
+	}
+}
+
+// RegisterMissing registers def under its name and those aliases that are
+// not already taken, so a discovered resource never shadows a registered one.
+func (r *Registry) RegisterMissing(def *ResourceDefinition, aliases ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range append([]string{def.Name}, aliases...) {
+		name = strings.ToLower(name)
+		if _, taken := r.resources[name]; !taken {
+			r.resources[name] = def
+		}
+	}
+}
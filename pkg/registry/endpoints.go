@@ -0,0 +1,69 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "endpoints",
+		Aliases: []string{"ep"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "endpoints",
+		},
+		Namespaced:    true,
+		DefaultFields: []string{"name", "addresses", "ports", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "Endpoints name",
+				Type:        "string",
+			},
+			"namespace": {
+				Name:        "namespace",
+				Aliases:     []string{"ns"},
+				JSONPath:    "{.metadata.namespace}",
+				Description: "Namespace",
+				Type:        "string",
+			},
+			"addresses": {
+				Name:        "addresses",
+				Aliases:     []string{"endpoints"},
+				JSONPath:    "{.subsets[*].addresses[*].ip}",
+				Description: "Ready addresses",
+				Type:        "list",
+			},
+			"not-ready": {
+				Name:        "not-ready",
+				JSONPath:    "{.subsets[*].notReadyAddresses[*].ip}",
+				Description: "Addresses not ready",
+				Type:        "list",
+			},
+			"ports": {
+				Name:        "ports",
+				JSONPath:    "{.subsets[*].ports[*].port}",
+				Description: "Ports",
+				Type:        "list",
+			},
+			"targets": {
+				Name:        "targets",
+				JSONPath:    "{.subsets[*].addresses[*].targetRef.name}",
+				Description: "Target objects (usually pods)",
+				Type:        "list",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
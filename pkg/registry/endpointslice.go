@@ -0,0 +1,82 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "endpointslice",
+		Aliases: []string{"endpointslices"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "discovery.k8s.io",
+			Version:  "v1",
+			Resource: "endpointslices",
+		},
+		Namespaced:    true,
+		DefaultFields: []string{"name", "address-type", "ports", "endpoints", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "EndpointSlice name",
+				Type:        "string",
+			},
+			"namespace": {
+				Name:        "namespace",
+				Aliases:     []string{"ns"},
+				JSONPath:    "{.metadata.namespace}",
+				Description: "Namespace",
+				Type:        "string",
+			},
+			"address-type": {
+				Name:        "address-type",
+				JSONPath:    "{.addressType}",
+				Description: "Address type (IPv4, IPv6, FQDN)",
+				Type:        "string",
+			},
+			"service": {
+				Name:        "service",
+				Aliases:     []string{"svc"},
+				Expr:        ".metadata.labels['kubernetes.io/service-name']",
+				Description: "Owning service",
+				Type:        "string",
+			},
+			"ports": {
+				Name:        "ports",
+				JSONPath:    "{.ports[*].port}",
+				Description: "Ports",
+				Type:        "list",
+			},
+			"endpoints": {
+				Name:        "endpoints",
+				Aliases:     []string{"addresses"},
+				JSONPath:    "{.endpoints[*].addresses[*]}",
+				Description: "Endpoint addresses",
+				Type:        "list",
+			},
+			"ready": {
+				Name:        "ready",
+				Expr:        "count(.endpoints[*].conditions.ready, true) + '/' + len(.endpoints)",
+				Description: "Ready endpoints (e.g. 2/3)",
+				Type:        "string",
+			},
+			"nodes": {
+				Name:        "nodes",
+				JSONPath:    "{.endpoints[*].nodeName}",
+				Description: "Nodes of the endpoints",
+				Type:        "list",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
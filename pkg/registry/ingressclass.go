@@ -0,0 +1,55 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "ingressclass",
+		Aliases: []string{"ingressclasses"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "networking.k8s.io",
+			Version:  "v1",
+			Resource: "ingressclasses",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "controller", "parameters", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "IngressClass name",
+				Type:        "string",
+			},
+			"controller": {
+				Name:        "controller",
+				JSONPath:    "{.spec.controller}",
+				Description: "Ingress controller",
+				Type:        "string",
+			},
+			"parameters": {
+				Name:        "parameters",
+				JSONPath:    "{.spec.parameters.name}",
+				Description: "Parameters object",
+				Type:        "string",
+			},
+			"default": {
+				Name:        "default",
+				Expr:        ".metadata.annotations['ingressclass.kubernetes.io/is-default-class'] == 'true'",
+				Description: "Whether this is the default class",
+				Type:        "string",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
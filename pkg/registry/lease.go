@@ -0,0 +1,74 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "lease",
+		Aliases: []string{"leases"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "coordination.k8s.io",
+			Version:  "v1",
+			Resource: "leases",
+		},
+		Namespaced:    true,
+		DefaultFields: []string{"name", "holder", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "Lease name",
+				Type:        "string",
+			},
+			"namespace": {
+				Name:        "namespace",
+				Aliases:     []string{"ns"},
+				JSONPath:    "{.metadata.namespace}",
+				Description: "Namespace",
+				Type:        "string",
+			},
+			"holder": {
+				Name:        "holder",
+				JSONPath:    "{.spec.holderIdentity}",
+				Description: "Current holder",
+				Type:        "string",
+			},
+			"duration": {
+				Name:        "duration",
+				JSONPath:    "{.spec.leaseDurationSeconds}",
+				Description: "Lease duration in seconds",
+				Type:        "int",
+			},
+			"acquire-time": {
+				Name:        "acquire-time",
+				JSONPath:    "{.spec.acquireTime}",
+				Description: "When the lease was acquired",
+				Type:        "time",
+			},
+			"renew-time": {
+				Name:        "renew-time",
+				JSONPath:    "{.spec.renewTime}",
+				Description: "When the holder last renewed the lease",
+				Type:        "time",
+			},
+			"transitions": {
+				Name:        "transitions",
+				JSONPath:    "{.spec.leaseTransitions}",
+				Description: "Number of holder changes",
+				Type:        "int",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -0,0 +1,80 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "limitrange",
+		Aliases: []string{"limitranges", "limits"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "limitranges",
+		},
+		Namespaced:    true,
+		DefaultFields: []string{"name", "types", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "LimitRange name",
+				Type:        "string",
+			},
+			"namespace": {
+				Name:        "namespace",
+				Aliases:     []string{"ns"},
+				JSONPath:    "{.metadata.namespace}",
+				Description: "Namespace",
+				Type:        "string",
+			},
+			"types": {
+				Name:        "types",
+				JSONPath:    "{.spec.limits[*].type}",
+				Description: "Limited object types (Container, Pod, PersistentVolumeClaim)",
+				Type:        "list",
+			},
+			"limits": {
+				Name:        "limits",
+				JSONPath:    "{.spec.limits}",
+				Description: "Limit items",
+				Type:        "list",
+			},
+			"default": {
+				Name:        "default",
+				JSONPath:    "{.spec.limits[*].default}",
+				Description: "Default limits",
+				Type:        "list",
+			},
+			"default-request": {
+				Name:        "default-request",
+				JSONPath:    "{.spec.limits[*].defaultRequest}",
+				Description: "Default requests",
+				Type:        "list",
+			},
+			"max": {
+				Name:        "max",
+				JSONPath:    "{.spec.limits[*].max}",
+				Description: "Maximum usage",
+				Type:        "list",
+			},
+			"min": {
+				Name:        "min",
+				JSONPath:    "{.spec.limits[*].min}",
+				Description: "Minimum usage",
+				Type:        "list",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
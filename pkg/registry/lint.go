@@ -0,0 +1,346 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bangmodtechnology/kselect/pkg/expr"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fieldTypes are the field types the executor and describe output understand.
+var fieldTypes = map[string]bool{"string": true, "int": true, "list": true, "map": true, "time": true}
+
+var (
+	pluginKeys = []string{"name", "aliases", "group", "version", "resource", "namespaced", "override", "extends", "default_fields", "fields"}
+
+	// definitionOnlyKeys may not appear in a plugin that extends a resource,
+	// since the extended resource already defines them.
+	definitionOnlyKeys = []string{"name", "group", "version", "resource", "namespaced"}
+	fieldKeys          = []string{"jsonpath", "expr", "type", "description", "aliases"}
+)
+
+// PluginError is a problem found in a plugin file. Line is 0 when the
+// problem cannot be tied to a line.
+type PluginError struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (e *PluginError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Message)
+}
+
+// LintPlugin checks a plugin file against the plugin schema and against the
+// resources already in reg, returning every problem found.
+func LintPlugin(path string, reg *Registry) []*PluginError {
+	_, problems := readPlugin(path, reg, nil)
+	return problems
+}
+
+// LintPluginLayers loads plugin layers into reg exactly as LoadPluginLayers
+// does and returns every problem found. Pass a clone of the registry to
+// check plugins without registering them.
+func LintPluginLayers(layers []string, reg *Registry) []*PluginError {
+	return loadLayers(layers, reg)
+}
+
+// readPlugin parses and validates a plugin file. The plugin is only usable
+// when no problems are returned. replaceable reports whether an existing
+// resource may be replaced without override: true; it may be nil.
+func readPlugin(path string, reg *Registry, replaceable func(*ResourceDefinition) bool) (*pluginDefinition, []*PluginError) {
+	l := &pluginLinter{file: path, replaceable: replaceable}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		l.report(nil, "%v", err)
+		return nil, l.problems
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		l.report(nil, "invalid YAML: %v", err)
+		return nil, l.problems
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		l.report(&doc, "plugin must be a YAML mapping")
+		return nil, l.problems
+	}
+	root := doc.Content[0]
+
+	var plugin pluginDefinition
+	if err := root.Decode(&plugin); err != nil {
+		l.report(root, "%v", err)
+		return nil, l.problems
+	}
+
+	l.checkKeys(root, pluginKeys, "")
+	l.lint(root, &plugin, reg)
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		return l.problems[i].Line < l.problems[j].Line
+	})
+	return &plugin, l.problems
+}
+
+type pluginLinter struct {
+	file        string
+	replaceable func(*ResourceDefinition) bool
+	problems    []*PluginError
+}
+
+func (l *pluginLinter) report(node *yaml.Node, format string, args ...interface{}) {
+	line := 0
+	if node != nil {
+		line = node.Line
+	}
+	l.problems = append(l.problems, &PluginError{File: l.file, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *pluginLinter) lint(root *yaml.Node, plugin *pluginDefinition, reg *Registry) {
+	fieldNames := make(map[string]string) // name or alias → field that owns it
+
+	if plugin.Extends != "" {
+		target, ok := reg.Get(plugin.Extends)
+		if !ok {
+			l.report(valueNode(root, "extends"), "extends unknown resource %q", plugin.Extends)
+		}
+		for _, key := range definitionOnlyKeys {
+			if node := valueNode(root, key); node != nil {
+				l.report(node, "%q is not allowed with extends", key)
+			}
+		}
+		if target != nil {
+			for name, f := range target.Fields {
+				fieldNames[name] = name
+				for _, a := range f.Aliases {
+					fieldNames[a] = name
+				}
+			}
+		}
+	} else {
+		for _, key := range []string{"name", "version", "resource"} {
+			if valueNode(root, key) == nil {
+				l.report(root, "missing required key %q", key)
+			}
+		}
+	}
+
+	// Fields, in file order
+	if fieldsNode := valueNode(root, "fields"); fieldsNode != nil {
+		for i := 0; i+1 < len(fieldsNode.Content); i += 2 {
+			keyNode, node := fieldsNode.Content[i], fieldsNode.Content[i+1]
+			if plugin.Override {
+				// Extensions may redefine fields of the extended resource
+				delete(fieldNames, keyNode.Value)
+			}
+			l.lintField(keyNode.Value, node, plugin.Fields[keyNode.Value], fieldNames)
+		}
+	}
+
+	// Default fields must name declared fields or aliases
+	if df := valueNode(root, "default_fields"); df != nil {
+		for _, item := range df.Content {
+			if _, ok := fieldNames[item.Value]; !ok {
+				l.report(item, "default field %q is not defined in fields", item.Value)
+			}
+		}
+	}
+
+	l.lintCollisions(root, plugin, reg)
+}
+
+func (l *pluginLinter) lintField(name string, node *yaml.Node, field pluginFieldDef, fieldNames map[string]string) {
+	if owner, taken := fieldNames[name]; taken {
+		if owner == name {
+			l.report(node, "field %q is already defined; set override: true to replace it", name)
+		} else {
+			l.report(node, "field %q duplicates an alias of field %q", name, owner)
+		}
+	}
+	fieldNames[name] = name
+
+	if node.Kind != yaml.MappingNode {
+		l.report(node, "field %q must be a mapping", name)
+		return
+	}
+	l.checkKeys(node, fieldKeys, "field "+name+": ")
+
+	switch {
+	case field.JSONPath == "" && field.Expr == "":
+		l.report(node, "field %q has no jsonpath or expr", name)
+	case field.JSONPath != "" && field.Expr != "":
+		l.report(valueNode(node, "expr"), "field %q has both jsonpath and expr", name)
+	case field.Expr != "":
+		if _, err := expr.Compile(field.Expr); err != nil {
+			l.report(valueNode(node, "expr"), "field %q has invalid expr %q: %v", name, field.Expr, err)
+		}
+	default:
+		if err := validateJSONPath(field.JSONPath); err != nil {
+			l.report(valueNode(node, "jsonpath"), "field %q has invalid jsonpath %q: %v; use expr: for anything else", name, field.JSONPath, err)
+		} else if suffix, fn := quantitySuffix(name); fn != "" {
+			// Quantities are no longer converted by the name of their field
+			path := strings.TrimSuffix(strings.TrimPrefix(field.JSONPath, "{"), "}")
+			l.report(valueNode(node, "jsonpath"), "field %q no longer converts its quantity by the %s suffix; use expr: \"%s(%s)\" instead of jsonpath",
+				name, suffix, fn, path)
+		}
+	}
+
+	if field.Type != "" && !fieldTypes[field.Type] {
+		l.report(valueNode(node, "type"), "field %q has unknown type %q (want one of %s)", name, field.Type, strings.Join(sortedKeys(fieldTypes), ", "))
+	}
+
+	if aliases := valueNode(node, "aliases"); aliases != nil {
+		for _, item := range aliases.Content {
+			if owner, taken := fieldNames[item.Value]; taken && owner != name {
+				l.report(item, "alias %q of field %q is already used by field %q", item.Value, name, owner)
+				continue
+			}
+			fieldNames[item.Value] = name
+		}
+	}
+}
+
+// lintCollisions applies the collision policy: a plugin may not reuse the
+// name or an alias of a built-in resource or another plugin unless it sets
+// override: true or the other plugin is in a lower layer.
+func (l *pluginLinter) lintCollisions(root *yaml.Node, plugin *pluginDefinition, reg *Registry) {
+	names := []*yaml.Node{valueNode(root, "name")}
+	if aliases := valueNode(root, "aliases"); aliases != nil {
+		names = append(names, aliases.Content...)
+	}
+
+	seen := make(map[string]bool)
+	for _, node := range names {
+		if node == nil || node.Value == "" {
+			continue
+		}
+		if seen[node.Value] {
+			l.report(node, "alias %q is listed twice", node.Value)
+			continue
+		}
+		seen[node.Value] = true
+
+		existing, ok := reg.Get(node.Value)
+		if !ok || existing.Source == l.file || plugin.Override {
+			continue
+		}
+		if plugin.Extends != "" && reg.resources[plugin.Extends] == existing {
+			continue
+		}
+		if l.replaceable != nil && l.replaceable(existing) {
+			continue
+		}
+		if existing.Source == "" {
+			l.report(node, "%q shadows built-in resource %s; set override: true to replace it", node.Value, existing.Name)
+		} else {
+			l.report(node, "%q is already defined by plugin %s; set override: true to replace it", node.Value, existing.Source)
+		}
+	}
+}
+
+// checkKeys reports mapping keys outside allowed, which are usually typos.
+func (l *pluginLinter) checkKeys(node *yaml.Node, allowed []string, prefix string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		known := false
+		for _, a := range allowed {
+			if key.Value == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			msg := fmt.Sprintf("%sunknown key %q", prefix, key.Value)
+			if suggestion := closestKey(key.Value, allowed); suggestion != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			l.report(key, "%s", msg)
+		}
+	}
+}
+
+// quantitySuffix returns the -m (millicores) or -mi (MiB) suffix of name and
+// the expr function converting a quantity to that unit, or "" and "".
+func quantitySuffix(name string) (suffix, fn string) {
+	switch {
+	case strings.HasSuffix(name, "-m"):
+		return "-m", "cpu"
+	case strings.HasSuffix(name, "-mi"):
+		return "-mi", "memory"
+	}
+	return "", ""
+}
+
+// pathPartRe matches one dot-separated part of a field path as the executor
+// reads it: a key, optionally followed by [n] or [*].
+var pathPartRe = regexp.MustCompile(`^[^.\[\]()?@*'"\s]+(\[(\d+|\*)\])?$`)
+
+// checkPathSubset checks that path, without braces, uses only the path
+// syntax the executor reads: .a.b, [n] and [*]. Filters, slices, recursive
+// descent and quoted keys are not supported.
+func checkPathSubset(path string) error {
+	if !strings.HasPrefix(path, ".") {
+		return fmt.Errorf("must start with .")
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		if !pathPartRe.MatchString(part) {
+			return fmt.Errorf("unsupported syntax (only .key, [n] and [*] are supported)")
+		}
+	}
+	return nil
+}
+
+// validateJSONPath checks that path is a {...} template in the subset of
+// jsonpath the executor reads.
+func validateJSONPath(path string) error {
+	if !strings.HasPrefix(path, "{") || !strings.HasSuffix(path, "}") {
+		return fmt.Errorf("must be wrapped in {}")
+	}
+	return checkPathSubset(strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}"))
+}
+
+// valueNode returns the value node for key in a mapping node, or nil.
+func valueNode(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// closestKey returns the allowed key sharing the longest prefix with key, if
+// the two share at least three characters.
+func closestKey(key string, allowed []string) string {
+	best, bestLen := "", 2
+	for _, a := range allowed {
+		n := 0
+		for n < len(a) && n < len(key) && a[n] == key[n] {
+			n++
+		}
+		if n > bestLen {
+			best, bestLen = a, n
+		}
+	}
+	return best
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,309 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintPluginReportsLines(t *testing.T) {
+	path := filepath.Join("testdata", "bad-plugin.yaml")
+	problems := LintPlugin(path, GetGlobalRegistry())
+
+	expected := []struct {
+		line int
+		text string
+	}{
+		{1, `missing required key "version"`},
+		{1, `"pod" shadows built-in resource pod`},
+		{4, `alias "widgets" is listed twice`},
+		{7, `unknown key "defualt_fields" (did you mean "default_fields"?)`},
+		{11, `default field "size" is not defined`},
+		{17, `invalid jsonpath`},
+		{18, `unknown type "text"`},
+		{20, `alias "name" of field "owner" is already used by field "name"`},
+	}
+	for _, want := range expected {
+		found := false
+		for _, p := range problems {
+			if p.Line == want.line && strings.Contains(p.Message, want.text) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected problem at line %d containing %q, got:\n%v", want.line, want.text, problems)
+		}
+	}
+	if len(problems) != len(expected) {
+		t.Errorf("Expected %d problems, got %d:\n%v", len(expected), len(problems), problems)
+	}
+	if !strings.HasPrefix(problems[0].Error(), path+":") {
+		t.Errorf("Expected error to start with file name, got %q", problems[0].Error())
+	}
+}
+
+func TestLintBundledPlugins(t *testing.T) {
+	files, err := PluginFiles(filepath.Join("..", "..", "plugins"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Expected bundled plugins, got %v (%v)", files, err)
+	}
+	for _, file := range files {
+		if problems := LintPlugin(file, GetGlobalRegistry()); len(problems) > 0 {
+			t.Errorf("Expected %s to lint clean, got %v", file, problems)
+		}
+	}
+}
+
+func TestLoadPluginOverride(t *testing.T) {
+	reg := NewRegistry()
+	builtin := &ResourceDefinition{Name: "widget", Aliases: []string{"widgets", "wd"}}
+	reg.Register(builtin)
+
+	plugin := `name: widget
+group: example.com
+version: v1
+resource: widgets
+fields:
+  name:
+    jsonpath: "{.metadata.name}"
+    type: string
+`
+	path := filepath.Join(t.TempDir(), "widget.yaml")
+	if err := os.WriteFile(path, []byte(plugin), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadPlugin(path, reg, nil); len(err) == 0 {
+		t.Fatal("Expected collision with built-in resource to be rejected")
+	}
+	if def, _ := reg.Get("widget"); def != builtin {
+		t.Error("Rejected plugin must not replace the built-in resource")
+	}
+
+	if err := os.WriteFile(path, []byte("override: true\n"+plugin), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadPlugin(path, reg, nil); len(err) > 0 {
+		t.Fatalf("Expected override to load, got %v", err)
+	}
+	def, _ := reg.Get("widget")
+	if def == builtin || def.Source != path {
+		t.Errorf("Expected plugin to replace built-in, got %+v", def)
+	}
+	if _, ok := reg.Get("wd"); ok {
+		t.Error("Expected aliases of the replaced resource to be removed")
+	}
+}
+
+func writePlugin(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPluginLayers(t *testing.T) {
+	widget := `name: widget
+group: example.com
+version: %s
+resource: widgets
+fields:
+  name:
+    jsonpath: "{.metadata.name}"
+    type: string
+`
+	user := filepath.Join(t.TempDir(), "user")
+	project := filepath.Join(t.TempDir(), "project")
+	writePlugin(t, user, "widget.yaml", strings.Replace(widget, "%s", "v1", 1))
+	projectPath := writePlugin(t, project, "widget.yaml", strings.Replace(widget, "%s", "v2", 1))
+	writePlugin(t, project, "widget-owner.yaml", `extends: widget
+fields:
+  owner:
+    jsonpath: "{.metadata.annotations.owner}"
+    type: string
+`)
+
+	reg := NewRegistry()
+	layers := []string{filepath.Join(t.TempDir(), "missing"), user, project}
+	if problems := loadLayers(layers, reg); len(problems) > 0 {
+		t.Fatalf("Expected layers to load, got %v", problems)
+	}
+	def, _ := reg.Get("widget")
+	if def.GroupVersionResource.Version != "v2" || def.Source != projectPath {
+		t.Errorf("Expected the project layer to win, got %s from %s", def.GroupVersionResource.Version, def.Source)
+	}
+	if _, ok := def.Fields["owner"]; !ok {
+		t.Error("Expected the extension to apply to the winning definition")
+	}
+
+	// Within a single layer a collision still needs override
+	writePlugin(t, project, "widget-copy.yaml", strings.Replace(widget, "%s", "v3", 1))
+	problems := loadLayers([]string{project}, NewRegistry())
+	if len(problems) != 1 || !strings.Contains(problems[0].Message, "override") {
+		t.Errorf("Expected one collision within the layer, got %v", problems)
+	}
+}
+
+func TestLoadPluginExtends(t *testing.T) {
+	reg := GetGlobalRegistry().Clone()
+	pod, _ := reg.Get("pod")
+	dir := t.TempDir()
+
+	path := writePlugin(t, dir, "pod-team.yaml", `extends: pod
+aliases: [workload]
+fields:
+  team:
+    jsonpath: "{.metadata.annotations.team}"
+    type: string
+`)
+	if problems := loadPlugin(path, reg, nil); len(problems) > 0 {
+		t.Fatalf("Expected extension to load, got %v", problems)
+	}
+	def, _ := reg.Get("workload")
+	if def.Name != "pod" || def.Fields["team"].JSONPath != "{.metadata.annotations.team}" {
+		t.Errorf("Expected pod with a team field, got %+v", def)
+	}
+	if def.Fields["name"].JSONPath != pod.Fields["name"].JSONPath || def.Source != "" {
+		t.Error("Expected the extended pod to keep its built-in fields and source")
+	}
+	if _, ok := pod.Fields["team"]; ok {
+		t.Error("Extending must not modify the original definition")
+	}
+
+	bad := writePlugin(t, dir, "bad.yaml", `extends: pod
+version: v2
+fields:
+  status:
+    jsonpath: "{.status.phase}"
+    type: string
+`)
+	problems := LintPlugin(bad, reg)
+	if len(problems) != 2 {
+		t.Fatalf("Expected 2 problems, got %v", problems)
+	}
+	if !strings.Contains(problems[0].Message, `"version"`) || !strings.Contains(problems[1].Message, `field "status" is already defined`) {
+		t.Errorf("Unexpected problems: %v", problems)
+	}
+
+	unknown := writePlugin(t, dir, "unknown.yaml", "extends: gadget\nfields: {}\n")
+	if problems := LintPlugin(unknown, reg); len(problems) != 1 || !strings.Contains(problems[0].Message, "gadget") {
+		t.Errorf("Expected unknown extends target to be reported, got %v", problems)
+	}
+}
+
+func TestPluginDirs(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, ".kselect")
+	work := filepath.Join(root, "app", "deploy")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(work, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(work)
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	t.Setenv("KSELECT_PLUGIN_PATH", "/first"+string(os.PathListSeparator)+"/second")
+
+	got := PluginDirs("./flag")
+	want := []string{SystemPluginDir, "/xdg/kselect/plugins", "/second", "/first", project, "./flag"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("PluginDirs() = %v, want %v", got, want)
+	}
+}
+
+func TestLintPluginExpr(t *testing.T) {
+	path := writePlugin(t, t.TempDir(), "widget.yaml", `name: widget
+group: example.com
+version: v1
+resource: widgets
+fields:
+  ready:
+    expr: "count(.status.parts[*].ready, true) + '/' + len(.spec.parts)"
+    type: string
+  size:
+    expr: "sum(.spec.parts[*].size"
+    type: int
+  both:
+    jsonpath: "{.spec.both}"
+    expr: ".spec.both"
+  neither:
+    type: string
+`)
+	problems := LintPlugin(path, NewRegistry())
+	expected := []string{`field "size" has invalid expr`, `field "both" has both jsonpath and expr`, `field "neither" has no jsonpath or expr`}
+	if len(problems) != len(expected) {
+		t.Fatalf("Expected %d problems, got %v", len(expected), problems)
+	}
+	for i, want := range expected {
+		if !strings.Contains(problems[i].Message, want) {
+			t.Errorf("Expected problem %d to contain %q, got %q", i, want, problems[i].Message)
+		}
+	}
+}
+
+func TestLintPluginQuantitySuffix(t *testing.T) {
+	path := writePlugin(t, t.TempDir(), "widget.yaml", `name: widget
+group: example.com
+version: v1
+resource: widgets
+fields:
+  cpu-m:
+    jsonpath: "{.spec.cpu}"
+    type: int
+  mem-mi:
+    jsonpath: "{.spec.memory}"
+    type: int
+  disk-mi:
+    expr: "memory(.spec.disk)"
+    type: int
+`)
+	problems := LintPlugin(path, NewRegistry())
+	expected := []string{
+		`field "cpu-m" no longer converts its quantity by the -m suffix; use expr: "cpu(.spec.cpu)"`,
+		`field "mem-mi" no longer converts its quantity by the -mi suffix; use expr: "memory(.spec.memory)"`,
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("Expected %d problems, got %v", len(expected), problems)
+	}
+	for i, want := range expected {
+		if !strings.Contains(problems[i].Message, want) {
+			t.Errorf("Expected problem %d to contain %q, got %q", i, want, problems[i].Message)
+		}
+	}
+}
+
+func TestLintPluginJSONPathSubset(t *testing.T) {
+	path := writePlugin(t, t.TempDir(), "widget.yaml", `name: widget
+group: example.com
+version: v1
+resource: widgets
+fields:
+  first:
+    jsonpath: "{.spec.parts[0].name}"
+  all:
+    jsonpath: "{.spec.parts[*].name}"
+  ready:
+    jsonpath: "{.status.conditions[?(@.type=='Ready')].status}"
+  some:
+    jsonpath: "{.spec.parts[0:2]}"
+  names:
+    jsonpath: "{..name}"
+`)
+	problems := LintPlugin(path, NewRegistry())
+	expected := []string{`field "ready" has invalid jsonpath`, `field "some" has invalid jsonpath`, `field "names" has invalid jsonpath`}
+	if len(problems) != len(expected) {
+		t.Fatalf("Expected %d problems, got %v", len(expected), problems)
+	}
+	for i, want := range expected {
+		if !strings.Contains(problems[i].Message, want) || !strings.Contains(problems[i].Message, "use expr:") {
+			t.Errorf("Expected problem %d to contain %q and point to expr, got %q", i, want, problems[i].Message)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,64 +11,180 @@ import (
 )
 
 type pluginDefinition struct {
-	Name          string                    `yaml:"name"`
-	Aliases       []string                  `yaml:"aliases"`
-	Group         string                    `yaml:"group"`
-	Version       string                    `yaml:"version"`
-	Resource      string                    `yaml:"resource"`
-	Namespaced    *bool                     `yaml:"namespaced"` // default true
-	DefaultFields []string                  `yaml:"default_fields"`
+	Name          string                    `yaml:"name,omitempty"`
+	Aliases       []string                  `yaml:"aliases,omitempty"`
+	Group         string                    `yaml:"group,omitempty"`
+	Version       string                    `yaml:"version,omitempty"`
+	Resource      string                    `yaml:"resource,omitempty"`
+	Namespaced    *bool                     `yaml:"namespaced,omitempty"` // default true
+	Override      bool                      `yaml:"override,omitempty"`   // replace a resource or field with the same name
+	Extends       string                    `yaml:"extends,omitempty"`    // add fields to this existing resource
+	DefaultFields []string                  `yaml:"default_fields,omitempty"`
 	Fields        map[string]pluginFieldDef `yaml:"fields"`
 }
 
 type pluginFieldDef struct {
-	JSONPath    string   `yaml:"jsonpath"`
-	Type        string   `yaml:"type"`
-	Description string   `yaml:"description"`
-	Aliases     []string `yaml:"aliases"`
+	JSONPath    string   `yaml:"jsonpath,omitempty"`
+	Expr        string   `yaml:"expr,omitempty"`
+	Type        string   `yaml:"type,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Aliases     []string `yaml:"aliases,omitempty"`
 }
 
+// LoadPlugins loads every *.yaml and *.yml plugin in dir into the global
+// registry. Invalid plugins are skipped; their problems are returned together.
 func LoadPlugins(dir string) error {
+	return LoadPluginLayers([]string{dir})
+}
+
+// LoadPluginLayers loads plugin layers into the global registry in order of
+// increasing precedence. Each layer is a plugin directory or file; missing
+// layers are skipped. A plugin replaces a plugin with the same name or alias
+// from a lower layer, while collisions within a layer or with built-in
+// resources need override: true. Plugins that extend a resource are applied
+// after every layer's definitions, so they extend the final resource.
+func LoadPluginLayers(layers []string) error {
+	return GetGlobalRegistry().LoadPluginLayers(layers)
+}
+
+// LoadPluginLayers loads plugin layers into r, like the LoadPluginLayers
+// function does into the global registry.
+func (r *Registry) LoadPluginLayers(layers []string) error {
+	problems := loadLayers(layers, r)
+	errs := make([]error, len(problems))
+	for i, p := range problems {
+		errs[i] = p
+	}
+	return errors.Join(errs...)
+}
+
+func loadLayers(layers []string, reg *Registry) []*PluginError {
+	type layerFile struct {
+		path  string
+		layer int
+	}
+	var definitions, extensions []layerFile
+	var problems []*PluginError
+
+	for i, layer := range layers {
+		files, err := PluginFiles(layer)
+		if err != nil {
+			problems = append(problems, &PluginError{File: layer, Message: err.Error()})
+			continue
+		}
+		for _, file := range files {
+			if isExtension(file) {
+				extensions = append(extensions, layerFile{file, i})
+			} else {
+				definitions = append(definitions, layerFile{file, i})
+			}
+		}
+	}
+
+	layerOf := make(map[string]int) // plugin file → layer it was loaded from
+	for _, f := range append(definitions, extensions...) {
+		f := f
+		replaceable := func(existing *ResourceDefinition) bool {
+			l, ok := layerOf[existing.Source]
+			return ok && l < f.layer
+		}
+		if errs := loadPlugin(f.path, reg, replaceable); len(errs) > 0 {
+			problems = append(problems, errs...)
+			continue
+		}
+		layerOf[f.path] = f.layer
+	}
+	return problems
+}
+
+// PluginFiles returns the plugin files in dir. A path to a single file is
+// returned as is; a missing path yields no files.
+func PluginFiles(dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err == nil && !info.IsDir() {
+		return []string{dir}, nil
+	}
+
 	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
 	if err != nil {
-		return fmt.Errorf("failed to glob plugin dir: %w", err)
+		return nil, fmt.Errorf("failed to glob plugin dir: %w", err)
 	}
 
 	ymlFiles, err := filepath.Glob(filepath.Join(dir, "*.yml"))
 	if err != nil {
-		return fmt.Errorf("failed to glob plugin dir: %w", err)
+		return nil, fmt.Errorf("failed to glob plugin dir: %w", err)
 	}
-	files = append(files, ymlFiles...)
-
-	for _, file := range files {
-		if err := loadPlugin(file); err != nil {
-			return fmt.Errorf("failed to load plugin %s: %w", file, err)
-		}
-	}
-	return nil
+	return append(files, ymlFiles...), nil
 }
 
-func loadPlugin(path string) error {
+// isExtension reports whether the plugin at path extends another resource.
+func isExtension(path string) bool {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return false
 	}
+	var head struct {
+		Extends string `yaml:"extends"`
+	}
+	return yaml.Unmarshal(data, &head) == nil && head.Extends != ""
+}
 
-	var plugin pluginDefinition
-	if err := yaml.Unmarshal(data, &plugin); err != nil {
-		return fmt.Errorf("failed to parse YAML: %w", err)
+func loadPlugin(path string, reg *Registry, replaceable func(*ResourceDefinition) bool) []*PluginError {
+	plugin, problems := readPlugin(path, reg, replaceable)
+	if len(problems) > 0 {
+		return problems
 	}
 
-	if plugin.Name == "" || plugin.Resource == "" {
-		return fmt.Errorf("plugin must have name and resource fields")
+	var def *ResourceDefinition
+	if plugin.Extends != "" {
+		def = plugin.extend(reg)
+	} else {
+		def = plugin.toResourceDefinition()
+		def.Source = path
 	}
 
+	// Drop every resource the plugin replaces, including their other aliases
+	for _, name := range append([]string{def.Name}, def.Aliases...) {
+		if existing, ok := reg.Get(name); ok {
+			reg.Unregister(existing)
+		}
+	}
+	reg.Register(def)
+	return nil
+}
+
+// extend returns a copy of the extended resource with the plugin's fields
+// and aliases added. The copy keeps the resource's Source, so extending a
+// built-in resource leaves it built-in.
+func (plugin *pluginDefinition) extend(reg *Registry) *ResourceDefinition {
+	target, _ := reg.Get(plugin.Extends)
+	def := *target
+	def.Aliases = append(append([]string(nil), target.Aliases...), plugin.Aliases...)
+	def.Fields = make(map[string]FieldDefinition, len(target.Fields)+len(plugin.Fields))
+	for name, f := range target.Fields {
+		def.Fields[name] = f
+	}
+	for name, f := range plugin.toResourceDefinition().Fields {
+		def.Fields[name] = f
+	}
+	if len(plugin.DefaultFields) > 0 {
+		def.DefaultFields = plugin.DefaultFields
+	}
+	return &def
+}
+
+// toResourceDefinition converts a plugin into the registry's representation.
+func (plugin *pluginDefinition) toResourceDefinition() *ResourceDefinition {
 	fields := make(map[string]FieldDefinition)
 	for name, f := range plugin.Fields {
 		fields[name] = FieldDefinition{
 			Name:        name,
 			Aliases:     f.Aliases,
 			JSONPath:    f.JSONPath,
+			Expr:        f.Expr,
 			Description: f.Description,
 			Type:        f.Type,
 		}
@@ -78,7 +195,7 @@ func loadPlugin(path string) error {
 		namespaced = *plugin.Namespaced
 	}
 
-	def := &ResourceDefinition{
+	return &ResourceDefinition{
 		Name:    plugin.Name,
 		Aliases: plugin.Aliases,
 		GroupVersionResource: schema.GroupVersionResource{
@@ -90,7 +207,4 @@ func loadPlugin(path string) error {
 		DefaultFields: plugin.DefaultFields,
 		Fields:        fields,
 	}
-
-	GetGlobalRegistry().Register(def)
-	return nil
 }
@@ -0,0 +1,69 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	// Rows are log lines, read from every container of the listed pods (or
+	// the pods selected by "FROM logs(query)"). The executor gives each line
+	// an object of its own: ts, line, container, pod (name, node) and the
+	// pod's metadata (namespace, labels).
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "logs",
+		Aliases: []string{"log"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "pods",
+		},
+		Namespaced:    true,
+		Scanner:       ScanLogs,
+		DefaultFields: []string{"ts", "pod", "container", "line"},
+		Fields: map[string]FieldDefinition{
+			"ts": {
+				Name:        "ts",
+				Aliases:     []string{"timestamp"},
+				JSONPath:    "{.ts}",
+				Description: "Time the line was written (RFC 3339)",
+				Type:        "string",
+			},
+			"line": {
+				Name:        "line",
+				Aliases:     []string{"message"},
+				JSONPath:    "{.line}",
+				Description: "Log line",
+				Type:        "string",
+			},
+			"container": {
+				Name:        "container",
+				JSONPath:    "{.container}",
+				Description: "Container name",
+				Type:        "string",
+			},
+			"pod": {
+				Name:        "pod",
+				JSONPath:    "{.pod.name}",
+				Description: "Pod name",
+				Type:        "string",
+			},
+			"node": {
+				Name:        "node",
+				JSONPath:    "{.pod.node}",
+				Description: "Node of the pod",
+				Type:        "string",
+			},
+			"namespace": {
+				Name:        "namespace",
+				Aliases:     []string{"ns"},
+				JSONPath:    "{.metadata.namespace}",
+				Description: "Namespace",
+				Type:        "string",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Pod labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -11,6 +11,7 @@ func init() {
 			Version:  "v1",
 			Resource: "nodes",
 		},
+		Metrics:       "nodes",
 		DefaultFields: []string{"name", "status", "roles", "version", "internal-ip", "age"},
 		Fields: map[string]FieldDefinition{
 			"name": {
@@ -21,8 +22,8 @@ func init() {
 			},
 			"status": {
 				Name:        "status",
-				JSONPath:    "{.status.conditions[*].type}",
-				Description: "Node status",
+				Expr:        "node_status(.)",
+				Description: "Ready, NotReady or Unknown, plus SchedulingDisabled",
 				Type:        "string",
 			},
 			"roles": {
@@ -79,6 +80,34 @@ func init() {
 				Description: "Memory capacity",
 				Type:        "string",
 			},
+			"cpu.usage": {
+				Name:        "cpu.usage",
+				Expr:        "cpu(.metrics.usage.cpu)",
+				Description: "CPU usage in millicores (metrics API)",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"mem.usage": {
+				Name:        "mem.usage",
+				Expr:        "memory(.metrics.usage.memory)",
+				Description: "Memory usage in MiB (metrics API)",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"cpu.usage-pct": {
+				Name:        "cpu.usage-pct",
+				Expr:        "round(cpu(.metrics.usage.cpu) * 100 / cpu(.status.allocatable.cpu))",
+				Description: "CPU usage as a percentage of allocatable",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"mem.usage-pct": {
+				Name:        "mem.usage-pct",
+				Expr:        "round(memory(.metrics.usage.memory) * 100 / memory(.status.allocatable.memory))",
+				Description: "Memory usage as a percentage of allocatable",
+				Type:        "int",
+				Metrics:     true,
+			},
 			"pods": {
 				Name:        "pods",
 				JSONPath:    "{.status.capacity.pods}",
@@ -0,0 +1,156 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	// Rows are nodes. The executor adds .allocation: allocatable cpu,
+	// memory, ephemeral storage and pods, and the requests and limits summed
+	// over the non-terminated pods scheduled on the node, as kubectl
+	// describe node reports them (cpu in millicores, the rest in MiB).
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "node_allocation",
+		Aliases: []string{"nodeallocation", "allocation"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "nodes",
+		},
+		Namespaced:    false, // cluster-scoped
+		Scanner:       ScanAllocation,
+		DefaultFields: []string{"name", "cpu.req-m", "cpu.alloc-m", "cpu.req-pct", "cpu.limit-pct", "mem.req-mi", "mem.alloc-mi", "mem.req-pct", "mem.limit-pct", "pods.count", "pods.pct"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "Node name",
+				Type:        "string",
+			},
+			"status": {
+				Name:        "status",
+				Expr:        "node_status(.)",
+				Description: "Node status",
+				Type:        "string",
+			},
+			"cpu.alloc-m": {
+				Name:        "cpu.alloc-m",
+				JSONPath:    "{.allocation.allocatable.cpu}",
+				Description: "Allocatable CPU in millicores",
+				Type:        "int",
+			},
+			"cpu.req-m": {
+				Name:        "cpu.req-m",
+				JSONPath:    "{.allocation.requests.cpu}",
+				Description: "Summed CPU requests of the node's pods in millicores",
+				Type:        "int",
+			},
+			"cpu.limit-m": {
+				Name:        "cpu.limit-m",
+				JSONPath:    "{.allocation.limits.cpu}",
+				Description: "Summed CPU limits of the node's pods in millicores",
+				Type:        "int",
+			},
+			"cpu.req-pct": {
+				Name:        "cpu.req-pct",
+				Expr:        "round(.allocation.requests.cpu * 100 / .allocation.allocatable.cpu)",
+				Description: "CPU requests as a percentage of allocatable",
+				Type:        "int",
+			},
+			"cpu.limit-pct": {
+				Name:        "cpu.limit-pct",
+				Expr:        "round(.allocation.limits.cpu * 100 / .allocation.allocatable.cpu)",
+				Description: "CPU limits as a percentage of allocatable",
+				Type:        "int",
+			},
+			"mem.alloc-mi": {
+				Name:        "mem.alloc-mi",
+				JSONPath:    "{.allocation.allocatable.memory}",
+				Description: "Allocatable Memory in MiB",
+				Type:        "int",
+			},
+			"mem.req-mi": {
+				Name:        "mem.req-mi",
+				JSONPath:    "{.allocation.requests.memory}",
+				Description: "Summed Memory requests of the node's pods in MiB",
+				Type:        "int",
+			},
+			"mem.limit-mi": {
+				Name:        "mem.limit-mi",
+				JSONPath:    "{.allocation.limits.memory}",
+				Description: "Summed Memory limits of the node's pods in MiB",
+				Type:        "int",
+			},
+			"mem.req-pct": {
+				Name:        "mem.req-pct",
+				Expr:        "round(.allocation.requests.memory * 100 / .allocation.allocatable.memory)",
+				Description: "Memory requests as a percentage of allocatable",
+				Type:        "int",
+			},
+			"mem.limit-pct": {
+				Name:        "mem.limit-pct",
+				Expr:        "round(.allocation.limits.memory * 100 / .allocation.allocatable.memory)",
+				Description: "Memory limits as a percentage of allocatable",
+				Type:        "int",
+			},
+			"ephemeral.alloc-mi": {
+				Name:        "ephemeral.alloc-mi",
+				JSONPath:    "{.allocation.allocatable.ephemeral}",
+				Description: "Allocatable Ephemeral storage in MiB",
+				Type:        "int",
+			},
+			"ephemeral.req-mi": {
+				Name:        "ephemeral.req-mi",
+				JSONPath:    "{.allocation.requests.ephemeral}",
+				Description: "Summed Ephemeral storage requests of the node's pods in MiB",
+				Type:        "int",
+			},
+			"ephemeral.limit-mi": {
+				Name:        "ephemeral.limit-mi",
+				JSONPath:    "{.allocation.limits.ephemeral}",
+				Description: "Summed Ephemeral storage limits of the node's pods in MiB",
+				Type:        "int",
+			},
+			"ephemeral.req-pct": {
+				Name:        "ephemeral.req-pct",
+				Expr:        "round(.allocation.requests.ephemeral * 100 / .allocation.allocatable.ephemeral)",
+				Description: "Ephemeral storage requests as a percentage of allocatable",
+				Type:        "int",
+			},
+			"ephemeral.limit-pct": {
+				Name:        "ephemeral.limit-pct",
+				Expr:        "round(.allocation.limits.ephemeral * 100 / .allocation.allocatable.ephemeral)",
+				Description: "Ephemeral storage limits as a percentage of allocatable",
+				Type:        "int",
+			},
+			"pods.alloc": {
+				Name:        "pods.alloc",
+				JSONPath:    "{.allocation.allocatable.pods}",
+				Description: "Allocatable pods",
+				Type:        "int",
+			},
+			"pods.count": {
+				Name:        "pods.count",
+				JSONPath:    "{.allocation.pods}",
+				Description: "Non-terminated pods on the node",
+				Type:        "int",
+			},
+			"pods.pct": {
+				Name:        "pods.pct",
+				Expr:        "round(.allocation.pods * 100 / .allocation.allocatable.pods)",
+				Description: "Pods as a percentage of allocatable",
+				Type:        "int",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Node labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -0,0 +1,96 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+// OwnerFields are virtual fields available on every resource. They are not
+// read through a JSONPath: the executor resolves them from ownerReferences,
+// walking the owner chain (e.g. Pod → ReplicaSet → Deployment) for root_owner.
+var OwnerFields = map[string]FieldDefinition{
+	"owner.kind": {
+		Name:        "owner.kind",
+		Description: "Kind of the controlling owner",
+		Type:        "string",
+	},
+	"owner.name": {
+		Name:        "owner.name",
+		Description: "Name of the controlling owner",
+		Type:        "string",
+	},
+	"root_owner": {
+		Name:        "root_owner",
+		Description: "Top-level owner as kind/name, resolved transitively",
+		Type:        "string",
+	},
+	"root_owner.kind": {
+		Name:        "root_owner.kind",
+		Description: "Kind of the top-level owner",
+		Type:        "string",
+	},
+	"root_owner.name": {
+		Name:        "root_owner.name",
+		Description: "Name of the top-level owner",
+		Type:        "string",
+	},
+}
+
+// IsOwnerField reports whether name is one of the virtual OwnerFields.
+func IsOwnerField(name string) bool {
+	_, ok := OwnerFields[name]
+	return ok
+}
+
+func init() {
+	// Rows for "FROM DESCENDANTS OF kind/name" are produced by the executor
+	// from every object owned (directly or transitively) by the root object.
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:                 "descendants",
+		Aliases:              []string{"descendant"},
+		GroupVersionResource: schema.GroupVersionResource{},
+		Namespaced:           true,
+		DefaultFields:        []string{"kind", "name", "owner.kind", "owner.name", "depth", "status", "age"},
+		Fields: map[string]FieldDefinition{
+			"kind": {
+				Name:        "kind",
+				JSONPath:    "{.kind}",
+				Description: "Object kind",
+				Type:        "string",
+			},
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "Object name",
+				Type:        "string",
+			},
+			"namespace": {
+				Name:        "namespace",
+				Aliases:     []string{"ns"},
+				JSONPath:    "{.metadata.namespace}",
+				Description: "Namespace",
+				Type:        "string",
+			},
+			"depth": {
+				Name:        "depth",
+				Description: "Ownership distance from the root object",
+				Type:        "int",
+			},
+			"status": {
+				Name:        "status",
+				JSONPath:    "{.status.phase}",
+				Description: "Status phase, if the kind has one",
+				Type:        "string",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
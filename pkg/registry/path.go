@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"strings"
+)
+
+// rawPathRoots are the top-level object keys that mark an undeclared dotted
+// field as a raw path on any resource (e.g. "spec.template.spec.nodeSelector").
+var rawPathRoots = map[string]bool{
+	"metadata": true,
+	"spec":     true,
+	"status":   true,
+	"data":     true,
+}
+
+// ParseFieldPath splits an object path into segments. Segments are separated
+// by dots; a quoted bracket key (['x/y'] or ["x/y"]) is one segment that may
+// contain dots, and an index ([0] or [*]) stays attached to its segment
+// ("containers[0]"). A leading dot is optional. ok is false for malformed paths.
+func ParseFieldPath(path string) (segments []string, ok bool) {
+	path = strings.TrimPrefix(path, ".")
+	var current strings.Builder
+	flush := func() bool {
+		if current.Len() == 0 {
+			return false
+		}
+		segments = append(segments, current.String())
+		current.Reset()
+		return true
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch ch := path[i]; ch {
+		case '.':
+			// A dot must end a segment, or follow a quoted key
+			if !flush() && (i == 0 || path[i-1] != ']') {
+				return nil, false
+			}
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, false
+			}
+			inner := path[i+1 : i+end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				// Quoted key: its own segment
+				flush()
+				segments = append(segments, inner[1:len(inner)-1])
+			} else {
+				if current.Len() == 0 {
+					return nil, false
+				}
+				current.WriteString(path[i : i+end+1])
+			}
+			i += end
+			if i+1 < len(path) && path[i+1] != '.' && path[i+1] != '[' {
+				return nil, false
+			}
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	if current.Len() > 0 {
+		flush()
+	} else if strings.HasSuffix(path, ".") || len(segments) == 0 {
+		return nil, false
+	}
+	return segments, true
+}
+
+// ResolveRawPath returns the object path that fieldName reads when it is not
+// a declared field. Accepted forms are a leading-dot path (".spec.replicas"),
+// a path under metadata, spec, status or data, a bracketed key on a map field
+// or on labels/annotations ("annotations['x/y']"), and, for resources with
+// RawPaths, any dotted path.
+func (d *ResourceDefinition) ResolveRawPath(fieldName string) ([]string, bool) {
+	if _, ok := d.Fields[fieldName]; ok {
+		return nil, false
+	}
+	if !strings.ContainsAny(fieldName, ".[") {
+		return nil, false
+	}
+	segments, ok := ParseFieldPath(fieldName)
+	if !ok || len(segments) < 2 {
+		return nil, false
+	}
+
+	switch {
+	case strings.HasPrefix(fieldName, "."), rawPathRoots[segments[0]]:
+		return segments, true
+	case strings.Contains(fieldName, "["):
+		// Bracketed key on a map field, read through the field's JSONPath
+		if fd, exists := d.Fields[segments[0]]; exists && fd.Type == "map" {
+			base, ok := ParseFieldPath(strings.TrimSuffix(strings.TrimPrefix(fd.JSONPath, "{"), "}"))
+			if !ok {
+				return nil, false
+			}
+			return append(base, segments[1:]...), true
+		}
+		if segments[0] == "labels" || segments[0] == "annotations" {
+			return append([]string{"metadata"}, segments...), true
+		}
+	case d.RawPaths:
+		return segments, true
+	}
+	return nil, false
+}
@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// SystemPluginDir holds plugins shared by every user of the machine.
+const SystemPluginDir = "/etc/kselect/plugins"
+
+// PluginDirs returns the plugin layers in order of increasing precedence:
+//
+//  1. /etc/kselect/plugins
+//  2. $XDG_CONFIG_HOME/kselect/plugins (default ~/.config/kselect/plugins)
+//  3. KSELECT_PLUGIN_PATH entries; like PATH, earlier entries win
+//  4. the nearest .kselect directory at or above the working directory
+//  5. extra, the -p flag, if set
+func PluginDirs(extra string) []string {
+	dirs := []string{SystemPluginDir}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+	if configHome != "" {
+		dirs = append(dirs, filepath.Join(configHome, "kselect", "plugins"))
+	}
+
+	pathList := filepath.SplitList(os.Getenv("KSELECT_PLUGIN_PATH"))
+	for i := len(pathList) - 1; i >= 0; i-- {
+		if pathList[i] != "" {
+			dirs = append(dirs, pathList[i])
+		}
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		if project := findProjectDir(wd); project != "" {
+			dirs = append(dirs, project)
+		}
+	}
+
+	if extra != "" {
+		dirs = append(dirs, extra)
+	}
+	return dirs
+}
+
+// findProjectDir returns the nearest .kselect directory at or above dir.
+func findProjectDir(dir string) string {
+	for {
+		candidate := filepath.Join(dir, ".kselect")
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
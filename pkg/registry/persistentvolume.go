@@ -39,8 +39,14 @@ func init() {
 			},
 			"status": {
 				Name:        "status",
-				JSONPath:    "{.status.phase}",
-				Description: "PV status",
+				Expr:        "volume_status(.)",
+				Description: "PV phase, or Terminating",
+				Type:        "string",
+			},
+			"reason": {
+				Name:        "reason",
+				JSONPath:    "{.status.reason}",
+				Description: "Reason for a failed PV",
 				Type:        "string",
 			},
 			"claim": {
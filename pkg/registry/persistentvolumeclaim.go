@@ -29,8 +29,14 @@ func init() {
 			},
 			"status": {
 				Name:        "status",
-				JSONPath:    "{.status.phase}",
-				Description: "PVC status",
+				Expr:        "volume_status(.)",
+				Description: "PVC phase, or Terminating",
+				Type:        "string",
+			},
+			"conditions": {
+				Name:        "conditions",
+				Expr:        "join(.status.conditions[*].type, ',')",
+				Description: "Active conditions (e.g. Resizing, FileSystemResizePending)",
 				Type:        "string",
 			},
 			"volume": {
@@ -12,7 +12,8 @@ func init() {
 			Resource: "pods",
 		},
 		Namespaced:    true,
-		DefaultFields: []string{"name", "status", "ip", "node", "restarts", "age"},
+		Metrics:       "pods",
+		DefaultFields: []string{"name", "ready", "status", "ip", "node", "restarts", "age"},
 		Fields: map[string]FieldDefinition{
 			"name": {
 				Name:        "name",
@@ -29,8 +30,26 @@ func init() {
 			},
 			"status": {
 				Name:        "status",
+				Expr:        "pod_status(.)",
+				Description: "Status as kubectl shows it (e.g. CrashLoopBackOff, Init:0/1)",
+				Type:        "string",
+			},
+			"phase": {
+				Name:        "phase",
 				JSONPath:    "{.status.phase}",
-				Description: "Pod status",
+				Description: "Pod phase",
+				Type:        "string",
+			},
+			"reason": {
+				Name:        "reason",
+				JSONPath:    "{.status.reason}",
+				Description: "Reason for the pod's state (e.g. Evicted)",
+				Type:        "string",
+			},
+			"ready": {
+				Name:        "ready",
+				Expr:        "count(.status.containerStatuses[*].ready, true) + '/' + len(.spec.containers)",
+				Description: "Ready containers (e.g. 1/2)",
 				Type:        "string",
 			},
 			"ip": {
@@ -71,27 +90,69 @@ func init() {
 			},
 			"cpu.req-m": {
 				Name:        "cpu.req-m",
-				JSONPath:    "{.spec.containers[*].resources.requests.cpu}",
-				Description: "CPU requests in millicores",
+				Expr:        "sum(cpu(.spec.containers[*].resources.requests.cpu))",
+				Description: "Total CPU requests in millicores",
 				Type:        "int",
 			},
 			"cpu.limit-m": {
 				Name:        "cpu.limit-m",
-				JSONPath:    "{.spec.containers[*].resources.limits.cpu}",
-				Description: "CPU limits in millicores",
+				Expr:        "sum(cpu(.spec.containers[*].resources.limits.cpu))",
+				Description: "Total CPU limits in millicores",
 				Type:        "int",
 			},
 			"mem.req-mi": {
 				Name:        "mem.req-mi",
-				JSONPath:    "{.spec.containers[*].resources.requests.memory}",
-				Description: "Memory requests in MiB",
+				Expr:        "sum(memory(.spec.containers[*].resources.requests.memory))",
+				Description: "Total memory requests in MiB",
 				Type:        "int",
 			},
 			"mem.limit-mi": {
 				Name:        "mem.limit-mi",
-				JSONPath:    "{.spec.containers[*].resources.limits.memory}",
-				Description: "Memory limits in MiB",
+				Expr:        "sum(memory(.spec.containers[*].resources.limits.memory))",
+				Description: "Total memory limits in MiB",
+				Type:        "int",
+			},
+			"cpu.usage": {
+				Name:        "cpu.usage",
+				Expr:        "sum(cpu(.metrics.containers[*].usage.cpu))",
+				Description: "CPU usage in millicores (metrics API)",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"mem.usage": {
+				Name:        "mem.usage",
+				Expr:        "sum(memory(.metrics.containers[*].usage.memory))",
+				Description: "Memory usage in MiB (metrics API)",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"cpu.usage-pct-of-req": {
+				Name:        "cpu.usage-pct-of-req",
+				Expr:        "round(sum(cpu(.metrics.containers[*].usage.cpu)) * 100 / sum(cpu(.spec.containers[*].resources.requests.cpu)))",
+				Description: "CPU usage as a percentage of requests",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"mem.usage-pct-of-req": {
+				Name:        "mem.usage-pct-of-req",
+				Expr:        "round(sum(memory(.metrics.containers[*].usage.memory)) * 100 / sum(memory(.spec.containers[*].resources.requests.memory)))",
+				Description: "Memory usage as a percentage of requests",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"cpu.usage-pct-of-limit": {
+				Name:        "cpu.usage-pct-of-limit",
+				Expr:        "round(sum(cpu(.metrics.containers[*].usage.cpu)) * 100 / sum(cpu(.spec.containers[*].resources.limits.cpu)))",
+				Description: "CPU usage as a percentage of limits",
+				Type:        "int",
+				Metrics:     true,
+			},
+			"mem.usage-pct-of-limit": {
+				Name:        "mem.usage-pct-of-limit",
+				Expr:        "round(sum(memory(.metrics.containers[*].usage.memory)) * 100 / sum(memory(.spec.containers[*].resources.limits.memory)))",
+				Description: "Memory usage as a percentage of limits",
 				Type:        "int",
+				Metrics:     true,
 			},
 			"image": {
 				Name:        "image",
@@ -101,8 +162,8 @@ func init() {
 			},
 			"restarts": {
 				Name:        "restarts",
-				JSONPath:    "{.status.containerStatuses[*].restartCount}",
-				Description: "Restart count",
+				Expr:        "sum(.status.containerStatuses[*].restartCount)",
+				Description: "Restart count of all containers",
 				Type:        "int",
 			},
 			"age": {
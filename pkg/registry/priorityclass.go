@@ -0,0 +1,61 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "priorityclass",
+		Aliases: []string{"priorityclasses", "pc"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "scheduling.k8s.io",
+			Version:  "v1",
+			Resource: "priorityclasses",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "value", "global-default", "preemption-policy", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "PriorityClass name",
+				Type:        "string",
+			},
+			"value": {
+				Name:        "value",
+				JSONPath:    "{.value}",
+				Description: "Priority value",
+				Type:        "int",
+			},
+			"global-default": {
+				Name:        "global-default",
+				JSONPath:    "{.globalDefault}",
+				Description: "Whether pods without a class get this one",
+				Type:        "string",
+			},
+			"preemption-policy": {
+				Name:        "preemption-policy",
+				JSONPath:    "{.preemptionPolicy}",
+				Description: "Preemption policy",
+				Type:        "string",
+			},
+			"description": {
+				Name:        "description",
+				JSONPath:    "{.description}",
+				Description: "Description",
+				Type:        "string",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
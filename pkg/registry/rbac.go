@@ -0,0 +1,100 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	// Rows are the permissions granted by RoleBindings and
+	// ClusterRoleBindings: one per subject, verb, API group, resource and
+	// resource name of each rule of the bound role. Aggregated ClusterRoles
+	// are expanded from their selectors. The executor builds the objects.
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "rbac",
+		Aliases: []string{"permission", "permissions"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "rbac.authorization.k8s.io",
+			Version:  "v1",
+			Resource: "rolebindings",
+		},
+		Namespaced:    true,
+		Scanner:       ScanRBAC,
+		DefaultFields: []string{"subject_kind", "subject", "namespace", "verb", "apiGroup", "resource", "via_binding", "via_role"},
+		Fields: map[string]FieldDefinition{
+			"subject_kind": {
+				Name:        "subject_kind",
+				JSONPath:    "{.subject.kind}",
+				Description: "User, Group or ServiceAccount",
+				Type:        "string",
+			},
+			"subject": {
+				Name:        "subject",
+				JSONPath:    "{.subject.name}",
+				Description: "Subject name",
+				Type:        "string",
+			},
+			"subject_namespace": {
+				Name:        "subject_namespace",
+				JSONPath:    "{.subject.namespace}",
+				Description: "Namespace of a ServiceAccount subject",
+				Type:        "string",
+			},
+			"namespace": {
+				Name:        "namespace",
+				Aliases:     []string{"ns"},
+				JSONPath:    "{.metadata.namespace}",
+				Description: "Namespace the permission applies in (* for cluster-wide)",
+				Type:        "string",
+			},
+			"verb": {
+				Name:        "verb",
+				JSONPath:    "{.verb}",
+				Description: "Verb, or * for all",
+				Type:        "string",
+			},
+			"apiGroup": {
+				Name:        "apiGroup",
+				Aliases:     []string{"api-group"},
+				JSONPath:    "{.apiGroup}",
+				Description: "API group (empty for core), or * for all",
+				Type:        "string",
+			},
+			"resource": {
+				Name:        "resource",
+				JSONPath:    "{.resource}",
+				Description: "Resource or subresource (e.g. pods/exec), or * for all",
+				Type:        "string",
+			},
+			"resourceName": {
+				Name:        "resourceName",
+				Aliases:     []string{"resource-name"},
+				JSONPath:    "{.resourceName}",
+				Description: "Object name the rule is limited to, if any",
+				Type:        "string",
+			},
+			"nonResourceURL": {
+				Name:        "nonResourceURL",
+				Aliases:     []string{"non-resource-url"},
+				JSONPath:    "{.nonResourceURL}",
+				Description: "Non-resource URL (e.g. /healthz), for cluster-wide rules",
+				Type:        "string",
+			},
+			"via_binding": {
+				Name:        "via_binding",
+				JSONPath:    "{.binding}",
+				Description: "Binding granting the permission, as Kind/name",
+				Type:        "string",
+			},
+			"via_role": {
+				Name:        "via_role",
+				JSONPath:    "{.role}",
+				Description: "Role bound, as Kind/name",
+				Type:        "string",
+			},
+			"aggregated_from": {
+				Name:        "aggregated_from",
+				JSONPath:    "{.aggregatedFrom}",
+				Description: "ClusterRole the rule was aggregated from, if not via_role itself",
+				Type:        "string",
+			},
+		},
+	})
+}
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"strings"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -13,17 +14,39 @@ type ResourceDefinition struct {
 	Namespaced           bool     // true = namespaced, false = cluster-scoped (e.g. node)
 	DefaultFields        []string // fields shown when user omits field list
 	Fields               map[string]FieldDefinition
+	RawPaths             bool    // any dotted path not in Fields (e.g. "foo.bar") reads the object directly
+	Source               string  // plugin file the definition came from; empty for built-in resources
+	Metrics              string  // metrics.k8s.io resource with usage for these objects ("pods", "nodes")
+	Scanner              Scanner // how rows are produced; ScanObjects for listed resources
 }
 
+// Scanner names how the executor produces the rows of a resource. Virtual
+// resources, whose rows are not the listed objects themselves, each have
+// their own.
+type Scanner string
+
+const (
+	ScanObjects    Scanner = ""           // one row per listed object
+	ScanContainers Scanner = "containers" // one row per container of each listed pod
+	ScanLogs       Scanner = "logs"       // one row per log line of each container of each listed pod
+	ScanRBAC       Scanner = "rbac"       // one row per permission granted through role bindings
+	ScanAllocation Scanner = "allocation" // nodes get .allocation, the summed requests and limits of their pods
+)
+
 type FieldDefinition struct {
 	Name        string
 	Aliases     []string // short names, e.g. "ns" for "namespace"
 	JSONPath    string
+	Expr        string // computed field; replaces JSONPath (see package expr)
+	Metrics     bool   // reads .metrics, the usage joined from metrics.k8s.io
 	Description string
 	Type        string // string, int, list, map, time
 }
 
+// Registry maps resource names and aliases to their definitions. It is safe
+// for concurrent use, since queries may discover resources while they run.
 type Registry struct {
+	mu        sync.RWMutex
 	resources map[string]*ResourceDefinition
 }
 
@@ -36,6 +59,8 @@ func NewRegistry() *Registry {
 }
 
 func (r *Registry) Register(def *ResourceDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.resources[def.Name] = def
 	for _, alias := range def.Aliases {
 		r.resources[alias] = def
@@ -100,12 +125,78 @@ func (d *ResourceDefinition) IsMapSubField(fieldName string) (baseName, subKey s
 	return "", "", false
 }
 
+// HasField reports whether fieldName can be referenced on this resource: a
+// declared field, a map sub-field (e.g. "labels.app"), a virtual owner field
+// or a raw object path (see ResolveRawPath).
+func (d *ResourceDefinition) HasField(fieldName string) bool {
+	if _, ok := d.Fields[fieldName]; ok {
+		return true
+	}
+	if _, _, ok := d.IsMapSubField(fieldName); ok {
+		return true
+	}
+	return IsOwnerField(fieldName) || d.IsRawPath(fieldName)
+}
+
+// IsRawPath reports whether fieldName is read directly from the object as a
+// path (e.g. "spec.strategy.type") rather than through a declared field.
+func (d *ResourceDefinition) IsRawPath(fieldName string) bool {
+	if _, _, ok := d.IsMapSubField(fieldName); ok || IsOwnerField(fieldName) {
+		return false
+	}
+	_, ok := d.ResolveRawPath(fieldName)
+	return ok
+}
+
+// Unregister removes def under its name and every alias.
+func (r *Registry) Unregister(def *ResourceDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, d := range r.resources {
+		if d == def {
+			delete(r.resources, name)
+		}
+	}
+}
+
+// Clone returns a registry holding the same definitions, so callers can
+// register query-scoped resources without touching the original.
+func (r *Registry) Clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	clone := NewRegistry()
+	for name, def := range r.resources {
+		clone.resources[name] = def
+	}
+	return clone
+}
+
+// NewRelationDefinition describes an in-memory query result, such as a WITH
+// common table expression, with one field per output column. It has no
+// GroupVersionResource since its rows never come from the API server.
+func NewRelationDefinition(name string, columns []string) *ResourceDefinition {
+	fields := make(map[string]FieldDefinition, len(columns))
+	for _, col := range columns {
+		fields[col] = FieldDefinition{Name: col, Description: "Column of " + name}
+	}
+	return &ResourceDefinition{
+		Name:          name,
+		Namespaced:    true,
+		DefaultFields: columns,
+		Fields:        fields,
+	}
+}
+
 func (r *Registry) Get(name string) (*ResourceDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	def, ok := r.resources[name]
 	return def, ok
 }
 
 func (r *Registry) ListResources() []*ResourceDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	seen := make(map[string]bool)
 	var resources []*ResourceDefinition
 
@@ -1,6 +1,11 @@
 package registry
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/bangmodtechnology/kselect/pkg/expr"
+)
 
 func TestResolveFieldAlias(t *testing.T) {
 	def := &ResourceDefinition{
@@ -39,3 +44,106 @@ func TestResolveFieldAlias(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFieldPath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+		ok    bool
+	}{
+		{".spec.strategy.rollingUpdate.maxSurge", []string{"spec", "strategy", "rollingUpdate", "maxSurge"}, true},
+		{"spec.template.spec.nodeSelector", []string{"spec", "template", "spec", "nodeSelector"}, true},
+		{"annotations['kubectl.kubernetes.io/last-applied-configuration']", []string{"annotations", "kubectl.kubernetes.io/last-applied-configuration"}, true},
+		{`metadata.labels["app.kubernetes.io/name"]`, []string{"metadata", "labels", "app.kubernetes.io/name"}, true},
+		{"spec.containers[0].image", []string{"spec", "containers[0]", "image"}, true},
+		{"data['a.b'].x", []string{"data", "a.b", "x"}, true},
+		{"spec..replicas", nil, false},
+		{"spec.", nil, false},
+		{"labels['app'", nil, false},
+		{"[0]", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseFieldPath(tt.input)
+		if ok != tt.ok || strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("ParseFieldPath(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestResolveRawPath(t *testing.T) {
+	def := &ResourceDefinition{
+		Name: "pod",
+		Fields: map[string]FieldDefinition{
+			"name":     {Name: "name", JSONPath: "{.metadata.name}"},
+			"selector": {Name: "selector", JSONPath: "{.spec.selector}", Type: "map"},
+			"cpu.req":  {Name: "cpu.req", JSONPath: "{.spec.containers[*].resources.requests.cpu}"},
+		},
+	}
+
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{".spec.nodeName", "spec|nodeName", true},
+		{"status.phase", "status|phase", true},
+		{"annotations['x/y']", "metadata|annotations|x/y", true},
+		{"labels['app.kubernetes.io/name']", "metadata|labels|app.kubernetes.io/name", true},
+		{"selector['app']", "spec|selector|app", true},
+		{"name", "", false},
+		{"cpu.req", "", false},
+		{"foo.bar", "", false},
+		{"p.name", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := def.ResolveRawPath(tt.input)
+		if ok != tt.ok || strings.Join(got, "|") != tt.want {
+			t.Errorf("ResolveRawPath(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+		if def.HasField(tt.input) != (tt.ok || tt.input == "name" || tt.input == "cpu.req") {
+			t.Errorf("HasField(%q) disagrees with ResolveRawPath", tt.input)
+		}
+	}
+
+	// Resources with RawPaths accept any dotted path
+	def.RawPaths = true
+	if got, ok := def.ResolveRawPath("foo.bar"); !ok || strings.Join(got, "|") != "foo|bar" {
+		t.Errorf("Expected foo.bar to resolve with RawPaths, got %q, %v", got, ok)
+	}
+}
+
+func TestBuiltinExpressionsCompile(t *testing.T) {
+	for _, def := range GetGlobalRegistry().ListResources() {
+		for name, field := range def.Fields {
+			if field.Expr == "" {
+				continue
+			}
+			if _, err := expr.Compile(field.Expr); err != nil {
+				t.Errorf("%s.%s: %v", def.Name, name, err)
+			}
+		}
+	}
+}
+
+func TestBuiltinResources(t *testing.T) {
+	reg := GetGlobalRegistry()
+	for _, def := range reg.ListResources() {
+		for _, name := range append([]string{def.Name}, def.Aliases...) {
+			if got, _ := reg.Get(name); got != def {
+				t.Errorf("%s: name %q resolves to %s", def.Name, name, got.Name)
+			}
+		}
+		for _, field := range def.DefaultFields {
+			if _, ok := def.Fields[field]; !ok && !IsOwnerField(field) {
+				t.Errorf("%s: default field %q is not defined", def.Name, field)
+			}
+		}
+	}
+	for _, name := range []string{"sc", "pc", "ep", "endpointslice", "lease", "mwc", "vwc", "crd", "apiservice", "ingressclass", "csr", "vpa", "limits", "runtimeclass", "csidriver", "volumeattachment"} {
+		if _, ok := reg.Get(name); !ok {
+			t.Errorf("Expected built-in resource %q", name)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "runtimeclass",
+		Aliases: []string{"runtimeclasses"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "node.k8s.io",
+			Version:  "v1",
+			Resource: "runtimeclasses",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "handler", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "RuntimeClass name",
+				Type:        "string",
+			},
+			"handler": {
+				Name:        "handler",
+				JSONPath:    "{.handler}",
+				Description: "CRI handler",
+				Type:        "string",
+			},
+			"node-selector": {
+				Name:        "node-selector",
+				JSONPath:    "{.scheduling.nodeSelector}",
+				Description: "Nodes that support the runtime",
+				Type:        "map",
+			},
+			"overhead": {
+				Name:        "overhead",
+				JSONPath:    "{.overhead.podFixed}",
+				Description: "Fixed pod overhead",
+				Type:        "map",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -12,7 +12,7 @@ func init() {
 			Resource: "statefulsets",
 		},
 		Namespaced:    true,
-		DefaultFields: []string{"name", "replicas", "ready", "age"},
+		DefaultFields: []string{"name", "ready-ratio", "age"},
 		Fields: map[string]FieldDefinition{
 			"name": {
 				Name:        "name",
@@ -39,6 +39,12 @@ func init() {
 				Description: "Ready replicas",
 				Type:        "int",
 			},
+			"ready-ratio": {
+				Name:        "ready-ratio",
+				Expr:        "coalesce(.status.readyReplicas, 0) + '/' + coalesce(.spec.replicas, 1)",
+				Description: "Ready of desired replicas (e.g. 2/3)",
+				Type:        "string",
+			},
 			"current": {
 				Name:        "current",
 				JSONPath:    "{.status.currentReplicas}",
@@ -89,26 +95,26 @@ func init() {
 			},
 			"cpu.req-m": {
 				Name:        "cpu.req-m",
-				JSONPath:    "{.spec.template.spec.containers[*].resources.requests.cpu}",
-				Description: "CPU requests in millicores",
+				Expr:        "sum(cpu(.spec.template.spec.containers[*].resources.requests.cpu))",
+				Description: "Total CPU requests in millicores",
 				Type:        "int",
 			},
 			"cpu.limit-m": {
 				Name:        "cpu.limit-m",
-				JSONPath:    "{.spec.template.spec.containers[*].resources.limits.cpu}",
-				Description: "CPU limits in millicores",
+				Expr:        "sum(cpu(.spec.template.spec.containers[*].resources.limits.cpu))",
+				Description: "Total CPU limits in millicores",
 				Type:        "int",
 			},
 			"mem.req-mi": {
 				Name:        "mem.req-mi",
-				JSONPath:    "{.spec.template.spec.containers[*].resources.requests.memory}",
-				Description: "Memory requests in MiB",
+				Expr:        "sum(memory(.spec.template.spec.containers[*].resources.requests.memory))",
+				Description: "Total memory requests in MiB",
 				Type:        "int",
 			},
 			"mem.limit-mi": {
 				Name:        "mem.limit-mi",
-				JSONPath:    "{.spec.template.spec.containers[*].resources.limits.memory}",
-				Description: "Memory limits in MiB",
+				Expr:        "sum(memory(.spec.template.spec.containers[*].resources.limits.memory))",
+				Description: "Total memory limits in MiB",
 				Type:        "int",
 			},
 			"age": {
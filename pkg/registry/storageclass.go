@@ -0,0 +1,80 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "storageclass",
+		Aliases: []string{"storageclasses", "sc"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "storage.k8s.io",
+			Version:  "v1",
+			Resource: "storageclasses",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "provisioner", "reclaim-policy", "binding-mode", "allow-expansion", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "StorageClass name",
+				Type:        "string",
+			},
+			"provisioner": {
+				Name:        "provisioner",
+				JSONPath:    "{.provisioner}",
+				Description: "Volume provisioner",
+				Type:        "string",
+			},
+			"reclaim-policy": {
+				Name:        "reclaim-policy",
+				Aliases:     []string{"reclaim"},
+				JSONPath:    "{.reclaimPolicy}",
+				Description: "Reclaim policy of provisioned volumes",
+				Type:        "string",
+			},
+			"binding-mode": {
+				Name:        "binding-mode",
+				JSONPath:    "{.volumeBindingMode}",
+				Description: "Volume binding mode (Immediate, WaitForFirstConsumer)",
+				Type:        "string",
+			},
+			"allow-expansion": {
+				Name:        "allow-expansion",
+				JSONPath:    "{.allowVolumeExpansion}",
+				Description: "Whether volumes can be expanded",
+				Type:        "string",
+			},
+			"default": {
+				Name:        "default",
+				Expr:        ".metadata.annotations['storageclass.kubernetes.io/is-default-class'] == 'true'",
+				Description: "Whether this is the default class",
+				Type:        "string",
+			},
+			"parameters": {
+				Name:        "parameters",
+				JSONPath:    "{.parameters}",
+				Description: "Provisioner parameters",
+				Type:        "map",
+			},
+			"mount-options": {
+				Name:        "mount-options",
+				JSONPath:    "{.mountOptions}",
+				Description: "Mount options",
+				Type:        "list",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -0,0 +1,79 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "validatingwebhookconfiguration",
+		Aliases: []string{"validatingwebhookconfigurations", "vwc"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "admissionregistration.k8s.io",
+			Version:  "v1",
+			Resource: "validatingwebhookconfigurations",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "webhooks", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "ValidatingWebhookConfiguration name",
+				Type:        "string",
+			},
+			"webhooks": {
+				Name:        "webhooks",
+				Expr:        "len(.webhooks)",
+				Description: "Number of webhooks",
+				Type:        "int",
+			},
+			"webhook-names": {
+				Name:        "webhook-names",
+				JSONPath:    "{.webhooks[*].name}",
+				Description: "Webhook names",
+				Type:        "list",
+			},
+			"services": {
+				Name:        "services",
+				JSONPath:    "{.webhooks[*].clientConfig.service.name}",
+				Description: "Backing services",
+				Type:        "list",
+			},
+			"urls": {
+				Name:        "urls",
+				JSONPath:    "{.webhooks[*].clientConfig.url}",
+				Description: "Webhook URLs",
+				Type:        "list",
+			},
+			"failure-policy": {
+				Name:        "failure-policy",
+				JSONPath:    "{.webhooks[*].failurePolicy}",
+				Description: "Failure policies (Fail, Ignore)",
+				Type:        "list",
+			},
+			"timeout": {
+				Name:        "timeout",
+				JSONPath:    "{.webhooks[*].timeoutSeconds}",
+				Description: "Timeouts in seconds",
+				Type:        "list",
+			},
+			"side-effects": {
+				Name:        "side-effects",
+				JSONPath:    "{.webhooks[*].sideEffects}",
+				Description: "Side effect classes",
+				Type:        "list",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "verticalpodautoscaler",
+		Aliases: []string{"verticalpodautoscalers", "vpa"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "autoscaling.k8s.io",
+			Version:  "v1",
+			Resource: "verticalpodautoscalers",
+		},
+		Namespaced:    true,
+		DefaultFields: []string{"name", "mode", "cpu", "memory", "provided", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "VerticalPodAutoscaler name",
+				Type:        "string",
+			},
+			"namespace": {
+				Name:        "namespace",
+				Aliases:     []string{"ns"},
+				JSONPath:    "{.metadata.namespace}",
+				Description: "Namespace",
+				Type:        "string",
+			},
+			"target": {
+				Name:        "target",
+				Aliases:     []string{"ref"},
+				Expr:        ".spec.targetRef.kind + '/' + .spec.targetRef.name",
+				Description: "Target workload",
+				Type:        "string",
+			},
+			"mode": {
+				Name:        "mode",
+				JSONPath:    "{.spec.updatePolicy.updateMode}",
+				Description: "Update mode (Off, Initial, Recreate, Auto)",
+				Type:        "string",
+			},
+			"cpu": {
+				Name:        "cpu",
+				JSONPath:    "{.status.recommendation.containerRecommendations[0].target.cpu}",
+				Description: "Recommended CPU of the first container",
+				Type:        "string",
+			},
+			"memory": {
+				Name:        "memory",
+				Aliases:     []string{"mem"},
+				JSONPath:    "{.status.recommendation.containerRecommendations[0].target.memory}",
+				Description: "Recommended memory of the first container",
+				Type:        "string",
+			},
+			"provided": {
+				Name:        "provided",
+				Expr:        "condition(.status.conditions, 'RecommendationProvided')",
+				Description: "RecommendationProvided condition status",
+				Type:        "string",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
@@ -0,0 +1,73 @@
+package registry
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+func init() {
+	GetGlobalRegistry().Register(&ResourceDefinition{
+		Name:    "volumeattachment",
+		Aliases: []string{"volumeattachments"},
+		GroupVersionResource: schema.GroupVersionResource{
+			Group:    "storage.k8s.io",
+			Version:  "v1",
+			Resource: "volumeattachments",
+		},
+		Namespaced:    false,
+		DefaultFields: []string{"name", "attacher", "pv", "node", "attached", "age"},
+		Fields: map[string]FieldDefinition{
+			"name": {
+				Name:        "name",
+				JSONPath:    "{.metadata.name}",
+				Description: "VolumeAttachment name",
+				Type:        "string",
+			},
+			"attacher": {
+				Name:        "attacher",
+				JSONPath:    "{.spec.attacher}",
+				Description: "CSI driver that attaches the volume",
+				Type:        "string",
+			},
+			"pv": {
+				Name:        "pv",
+				JSONPath:    "{.spec.source.persistentVolumeName}",
+				Description: "Persistent volume",
+				Type:        "string",
+			},
+			"node": {
+				Name:        "node",
+				JSONPath:    "{.spec.nodeName}",
+				Description: "Node the volume is attached to",
+				Type:        "string",
+			},
+			"attached": {
+				Name:        "attached",
+				JSONPath:    "{.status.attached}",
+				Description: "Whether the volume is attached",
+				Type:        "string",
+			},
+			"attach-error": {
+				Name:        "attach-error",
+				JSONPath:    "{.status.attachError.message}",
+				Description: "Last attach error",
+				Type:        "string",
+			},
+			"detach-error": {
+				Name:        "detach-error",
+				JSONPath:    "{.status.detachError.message}",
+				Description: "Last detach error",
+				Type:        "string",
+			},
+			"age": {
+				Name:        "age",
+				JSONPath:    "{.metadata.creationTimestamp}",
+				Description: "Age",
+				Type:        "time",
+			},
+			"labels": {
+				Name:        "labels",
+				JSONPath:    "{.metadata.labels}",
+				Description: "Labels",
+				Type:        "map",
+			},
+		},
+	})
+}
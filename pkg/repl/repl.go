@@ -1,8 +1,11 @@
 package repl
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,6 +26,7 @@ type REPL struct {
 	namespace     string
 	allNamespaces bool
 	useColor      bool
+	params        map[string]string // placeholder values set with \set :name value
 }
 
 // Config holds REPL configuration
@@ -31,6 +35,7 @@ type Config struct {
 	Namespace     string
 	AllNamespaces bool
 	UseColor      bool
+	Params        map[string]string // initial placeholder values, e.g. from --param
 }
 
 // New creates a new REPL instance
@@ -40,6 +45,11 @@ func New(exec *executor.Executor, config Config) (*REPL, error) {
 		return nil, fmt.Errorf("failed to initialize history: %w", err)
 	}
 
+	params := make(map[string]string, len(config.Params))
+	for name, value := range config.Params {
+		params[name] = value
+	}
+
 	format := output.FormatTable
 	if config.OutputFormat != "" {
 		format = output.Format(config.OutputFormat)
@@ -53,6 +63,7 @@ func New(exec *executor.Executor, config Config) (*REPL, error) {
 		namespace:     config.Namespace,
 		allNamespaces: config.AllNamespaces,
 		useColor:      config.UseColor,
+		params:        params,
 	}, nil
 }
 
@@ -101,6 +112,10 @@ func (r *REPL) executeLine(line string) {
 		fmt.Fprintf(os.Stderr, "Error parsing query: %v\n", err)
 		return
 	}
+	if err := parser.Bind(query, r.params); err != nil {
+		fmt.Fprintf(os.Stderr, "Error binding parameters: %v (set it with \\set :name value)\n", err)
+		return
+	}
 
 	// Apply namespace settings
 	if r.allNamespaces {
@@ -115,10 +130,20 @@ func (r *REPL) executeLine(line string) {
 	spin := output.NewSpinner(fmt.Sprintf("Fetching %s...", query.Resource))
 	spin.Start()
 	start := time.Now()
-	results, fields, err := r.executor.Execute(query)
+	cacheBefore := r.executor.CacheStats()
+	// Ctrl-C cancels the query instead of ending the session
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	results, fields, err := r.executor.ExecuteContext(ctx, query)
+	cancelled := ctx.Err() != nil
+	stop()
 	elapsed := time.Since(start)
+	cacheStatus := r.executor.CacheStats().Sub(cacheBefore).String()
 	spin.Stop()
 	if err != nil {
+		if cancelled {
+			fmt.Fprintln(os.Stderr, "Query cancelled")
+			return
+		}
 		fmt.Fprintf(os.Stderr, "Error executing query: %v\n", err)
 		return
 	}
@@ -126,6 +151,7 @@ func (r *REPL) executeLine(line string) {
 	// Format output
 	formatter := output.NewFormatter(r.outputFormat)
 	formatter.SetElapsed(elapsed)
+	formatter.SetCacheStatus(cacheStatus)
 	if err := formatter.Print(results, fields); err != nil {
 		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
 		return
@@ -152,7 +178,9 @@ func (r *REPL) completer(d prompt.Document) []prompt.Suggest {
 			{Text: "\\list", Description: "List saved queries"},
 			{Text: "\\describe", Description: "Describe a resource"},
 			{Text: "\\resources", Description: "List available resources"},
+			{Text: "\\refresh", Description: "Drop cached objects"},
 			{Text: "\\set", Description: "Set REPL options"},
+			{Text: "\\unset", Description: "Remove a query parameter"},
 			{Text: "\\show", Description: "Show current settings"},
 			{Text: "\\exit", Description: "Exit REPL"},
 			{Text: "\\quit", Description: "Exit REPL"},
@@ -177,6 +205,8 @@ func (r *REPL) completer(d prompt.Document) []prompt.Suggest {
 		{Text: "RIGHT JOIN", Description: "Right join"},
 		{Text: "ON", Description: "Join condition"},
 		{Text: "DESCRIBE", Description: "Show resource schema"},
+		{Text: "EXPLAIN", Description: "Show the query plan"},
+		{Text: "EXPLAIN ANALYZE", Description: "Run the query and time each step"},
 		{Text: "ASC", Description: "Ascending order"},
 		{Text: "DESC", Description: "Descending order"},
 		{Text: "LIKE", Description: "Pattern matching"},
@@ -258,8 +288,17 @@ func (r *REPL) handleCommand(cmd string) {
 		r.describeResource(args)
 	case "\\resources", "\\res":
 		r.listResources()
+	case "\\refresh":
+		r.executor.Refresh()
+		fmt.Println("Cache cleared; the next query reads the cluster")
 	case "\\set":
+		if len(args) > 0 && strings.HasPrefix(args[0], ":") {
+			r.setParam(args)
+			return
+		}
 		r.setSetting(args)
+	case "\\unset":
+		r.unsetParam(args)
 	case "\\show":
 		r.showSettings()
 	case "\\exit", "\\quit", "\\q":
@@ -280,7 +319,10 @@ func (r *REPL) showHelp() {
 	fmt.Println("  \\list                List all saved queries")
 	fmt.Println("  \\describe <resource> Show resource schema")
 	fmt.Println("  \\resources, \\res     List available resources")
-	fmt.Println("  \\set <key> <value>   Set REPL option (format, namespace, color)")
+	fmt.Println("  \\refresh             Drop cached objects so the next query reads the cluster")
+	fmt.Println("  \\set <key> <value>   Set REPL option (format, namespace, color, timeout)")
+	fmt.Println("  \\set :name <value>   Set the value of the :name (or :1 for $1) query placeholder")
+	fmt.Println("  \\unset :name         Remove a placeholder value")
 	fmt.Println("  \\show                Show current settings")
 	fmt.Println("  \\exit, \\quit, \\q     Exit REPL")
 	fmt.Println()
@@ -288,6 +330,8 @@ func (r *REPL) showHelp() {
 	fmt.Println("  name,status FROM pod WHERE namespace=default")
 	fmt.Println("  name,replicas FROM deployment ORDER BY name")
 	fmt.Println("  namespace, COUNT as total FROM pod -A GROUP BY namespace")
+	fmt.Println("  \\set :ns prod")
+	fmt.Println("  name,restarts FROM pod WHERE namespace = :ns")
 	fmt.Println()
 }
 
@@ -379,7 +423,7 @@ func (r *REPL) listResources() {
 func (r *REPL) setSetting(args []string) {
 	if len(args) < 2 {
 		fmt.Println("Usage: \\set <key> <value>")
-		fmt.Println("Keys: format, namespace, color")
+		fmt.Println("Keys: format, namespace, color, timeout")
 		return
 	}
 
@@ -412,6 +456,14 @@ func (r *REPL) setSetting(args []string) {
 			output.SetColorEnabled(false)
 			fmt.Println("Color output disabled")
 		}
+	case "timeout":
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			fmt.Printf("Invalid timeout: %s (e.g. 30s, 0 for none)\n", value)
+			return
+		}
+		r.executor.Timeout = timeout
+		fmt.Printf("Query timeout set to: %s\n", formatTimeout(timeout))
 	default:
 		fmt.Printf("Unknown setting: %s\n", key)
 	}
@@ -428,5 +480,54 @@ func (r *REPL) showSettings() {
 		fmt.Printf("  Namespace:       %s (current context)\n", r.executor.CurrentNamespace)
 	}
 	fmt.Printf("  Color output:    %v\n", r.useColor)
+	fmt.Printf("  Query timeout:   %s\n", formatTimeout(r.executor.Timeout))
+	if len(r.params) > 0 {
+		names := make([]string, 0, len(r.params))
+		for name := range r.params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Println("  Parameters:")
+		for _, name := range names {
+			fmt.Printf("    :%-14s %q\n", name, r.params[name])
+		}
+	}
 	fmt.Println()
 }
+
+// setParam sets a placeholder value: "\set :ns prod". The value is the rest
+// of the line, with one pair of surrounding quotes removed.
+func (r *REPL) setParam(args []string) {
+	name := strings.TrimPrefix(args[0], ":")
+	if name == "" || len(args) < 2 {
+		fmt.Println("Usage: \\set :name <value>")
+		return
+	}
+	value := strings.Join(args[1:], " ")
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	r.params[name] = value
+	fmt.Printf("Parameter :%s set to: %s\n", name, value)
+}
+
+func (r *REPL) unsetParam(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: \\unset :name")
+		return
+	}
+	name := strings.TrimPrefix(args[0], ":")
+	if _, ok := r.params[name]; !ok {
+		fmt.Printf("No parameter named :%s\n", name)
+		return
+	}
+	delete(r.params, name)
+	fmt.Printf("Parameter :%s removed\n", name)
+}
+
+func formatTimeout(timeout time.Duration) string {
+	if timeout <= 0 {
+		return "none"
+	}
+	return timeout.String()
+}
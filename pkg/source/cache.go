@@ -0,0 +1,154 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache keeps the lists of another Source for a while, so a resource listed
+// again with the same namespace and selectors, by a subquery or join of the
+// same query or by a later query of a REPL or TUI session, is served from
+// memory. Concurrent lists of the same key share one read of the source.
+// Discovery, logs and watches go to the source. Safe for concurrent use.
+type Cache struct {
+	src Source
+	ttl time.Duration
+	now func() time.Time
+
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	stats   CacheStats
+}
+
+type cacheEntry struct {
+	list    *unstructured.UnstructuredList
+	expires time.Time
+}
+
+// CacheStats counts the lists served from the cache (Hits) and from the
+// source (Misses).
+type CacheStats struct {
+	Hits   int
+	Misses int
+}
+
+// Sub returns the lists counted since prev.
+func (s CacheStats) Sub(prev CacheStats) CacheStats {
+	return CacheStats{Hits: s.Hits - prev.Hits, Misses: s.Misses - prev.Misses}
+}
+
+// String describes where the lists came from: "cached" if all of them came
+// from the cache, "partly cached" if some did, and "" if none did.
+func (s CacheStats) String() string {
+	switch {
+	case s.Hits == 0:
+		return ""
+	case s.Misses == 0:
+		return "cached"
+	}
+	return "partly cached"
+}
+
+// NewCache returns a Cache keeping the lists of src for ttl.
+func NewCache(src Source, ttl time.Duration) *Cache {
+	return &Cache{src: src, ttl: ttl, now: time.Now, entries: make(map[string]cacheEntry)}
+}
+
+func (c *Cache) List(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	key := gvr.String() + "|" + namespace + "|" + opts.LabelSelector + "|" + opts.FieldSelector
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && c.now().Before(entry.expires) {
+		c.stats.Hits++
+		c.mu.Unlock()
+		// Callers may change what they list
+		return entry.list.DeepCopy(), nil
+	}
+	c.mu.Unlock()
+
+	read := false
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		read = true
+		list, err := c.src.List(ctx, gvr, namespace, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.stats.Misses++
+		now := c.now()
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.entries[key] = cacheEntry{list: list.DeepCopy(), expires: now.Add(c.ttl)}
+		return list, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := v.(*unstructured.UnstructuredList)
+	if read {
+		return list, nil
+	}
+
+	// Lists shared with another caller count as hits
+	c.mu.Lock()
+	c.stats.Hits++
+	c.mu.Unlock()
+	return list.DeepCopy(), nil
+}
+
+// Invalidate drops every cached list, so the next lists read the source.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cacheEntry)
+}
+
+// Stats returns the lists counted so far.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *Cache) Watch(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	w, ok := c.src.(Watcher)
+	if !ok {
+		return nil, fmt.Errorf("watching is not supported by this source")
+	}
+	return w.Watch(ctx, gvr, namespace, opts)
+}
+
+func (c *Cache) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	d, ok := c.src.(Discoverer)
+	if !ok {
+		return nil, fmt.Errorf("discovery is not supported by this source")
+	}
+	return d.ServerPreferredResources()
+}
+
+func (c *Cache) Logs(ctx context.Context, namespace, pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	l, ok := c.src.(LogStreamer)
+	if !ok {
+		return nil, fmt.Errorf("reading logs is not supported by this source")
+	}
+	return l.Logs(ctx, namespace, pod, opts)
+}
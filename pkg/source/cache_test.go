@@ -0,0 +1,168 @@
+package source
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// countingSource counts the lists that reach it.
+type countingSource struct {
+	*Memory
+	lists int
+}
+
+func (s *countingSource) List(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	s.lists++
+	return s.Memory.List(ctx, gvr, namespace, opts)
+}
+
+func TestCache(t *testing.T) {
+	src := &countingSource{Memory: NewMemory([]unstructured.Unstructured{
+		object("v1", "Pod", "prod", "web-1", map[string]interface{}{"app": "web"}),
+		object("v1", "Pod", "prod", "api-1", map[string]interface{}{"app": "api"}),
+	})}
+	now := time.Unix(0, 0)
+	c := NewCache(src, time.Minute)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+	list := func(namespace, selector string) *unstructured.UnstructuredList {
+		t.Helper()
+		l, err := c.List(ctx, podsGVR, namespace, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		return l
+	}
+
+	list("prod", "")
+	first := list("prod", "")
+	if src.lists != 1 || c.Stats() != (CacheStats{Hits: 1, Misses: 1}) {
+		t.Errorf("Expected the second list from the cache, got %d lists and %+v", src.lists, c.Stats())
+	}
+	if got := names(first); got != "web-1,api-1" {
+		t.Errorf("cached pods = %s", got)
+	}
+	// Cached lists are copies
+	first.Items[0].SetName("changed")
+	if got := names(list("prod", "")); got != "web-1,api-1" {
+		t.Errorf("pods after changing a cached list = %s", got)
+	}
+
+	// Namespace and selectors are part of the key
+	if got := names(list("prod", "app=api")); got != "api-1" {
+		t.Errorf("selected pods = %s, want api-1", got)
+	}
+	list("", "")
+	if src.lists != 3 {
+		t.Errorf("Expected 3 lists from the source, got %d", src.lists)
+	}
+
+	now = now.Add(time.Minute)
+	list("prod", "")
+	if src.lists != 4 {
+		t.Errorf("Expected an expired list to be read again, got %d lists", src.lists)
+	}
+
+	c.Invalidate()
+	list("prod", "")
+	if src.lists != 5 {
+		t.Errorf("Expected an invalidated list to be read again, got %d lists", src.lists)
+	}
+}
+
+// blockingSource counts the lists that reach it and holds them until release
+// is closed.
+type blockingSource struct {
+	*Memory
+	lists   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSource) List(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if s.lists.Add(1) == 1 {
+		close(s.started)
+	}
+	<-s.release
+	return s.Memory.List(ctx, gvr, namespace, opts)
+}
+
+func TestCacheConcurrentLists(t *testing.T) {
+	src := &blockingSource{
+		Memory: NewMemory([]unstructured.Unstructured{
+			object("v1", "Pod", "prod", "web-1", map[string]interface{}{"app": "web"}),
+		}),
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	c := NewCache(src, time.Minute)
+
+	var wg sync.WaitGroup
+	lists := make([]*unstructured.UnstructuredList, 2)
+	list := func(i int) {
+		defer wg.Done()
+		l, err := c.List(context.Background(), podsGVR, "prod", metav1.ListOptions{})
+		if err != nil {
+			t.Errorf("List() error = %v", err)
+		}
+		lists[i] = l
+	}
+	wg.Add(2)
+	go list(0)
+	<-src.started
+	go list(1)
+	// Give the second list time to wait on the first
+	time.Sleep(50 * time.Millisecond)
+	close(src.release)
+	wg.Wait()
+
+	if got := src.lists.Load(); got != 1 {
+		t.Errorf("Expected concurrent lists to read the source once, got %d", got)
+	}
+	if c.Stats() != (CacheStats{Hits: 1, Misses: 1}) {
+		t.Errorf("Stats() = %+v, want one hit and one miss", c.Stats())
+	}
+	if lists[0] == nil || lists[1] == nil || lists[0] == lists[1] {
+		t.Fatal("Expected each caller to get its own list")
+	}
+	if names(lists[0]) != "web-1" || names(lists[1]) != "web-1" {
+		t.Errorf("lists = %s and %s, want web-1", names(lists[0]), names(lists[1]))
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	for _, tt := range []struct {
+		stats CacheStats
+		want  string
+	}{
+		{CacheStats{}, ""},
+		{CacheStats{Misses: 2}, ""},
+		{CacheStats{Hits: 2}, "cached"},
+		{CacheStats{Hits: 1, Misses: 1}, "partly cached"},
+	} {
+		if got := tt.stats.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.stats, got, tt.want)
+		}
+	}
+	if got := (CacheStats{Hits: 5, Misses: 3}).Sub(CacheStats{Hits: 2, Misses: 3}); got != (CacheStats{Hits: 3}) {
+		t.Errorf("Sub() = %+v", got)
+	}
+}
+
+func TestCacheForwards(t *testing.T) {
+	dump := &Dump{Memory: NewMemory(nil), logs: map[string][]byte{}}
+	c := NewCache(dump, time.Minute)
+	if _, err := c.ServerPreferredResources(); err != nil {
+		t.Errorf("ServerPreferredResources() error = %v", err)
+	}
+	if _, err := c.Watch(context.Background(), podsGVR, "", metav1.ListOptions{}); err == nil {
+		t.Error("Watch() of a source that cannot watch succeeded")
+	}
+}
@@ -0,0 +1,292 @@
+package source
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bangmodtechnology/kselect/pkg/manifest"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Dump serves the objects and pod logs of a point-in-time snapshot of a
+// cluster: a directory or tar archive (optionally gzipped) written by
+//
+//   - kubectl cluster-info dump --output-directory: lists of objects in
+//     [<namespace>/]<resource>.json and the logs of each pod, one container
+//     after another, in <namespace>/<pod>/logs.txt;
+//   - oc adm must-gather: namespaces/<namespace>/<group>/<resource>.yaml,
+//     namespaces/<namespace>/pods/<pod>/<pod>.yaml,
+//     cluster-scoped-resources/<group>/<resource>/<name>.yaml and container
+//     logs in namespaces/<namespace>/pods/<pod>/<container>/<container>/logs/current.log.
+//
+// Objects that do not name their kind get it from their file, as do
+// must-gather objects without a namespace. YAML and JSON files that are not
+// Kubernetes objects are skipped.
+type Dump struct {
+	*Memory
+	logs map[string][]byte // container logs by namespace/pod/container
+}
+
+// NewDump loads the cluster dump in path, a directory or tar archive.
+func NewDump(path string) (*Dump, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump: %w", err)
+	}
+
+	l := &dumpLoader{logs: make(map[string][]byte)}
+	if info.IsDir() {
+		err = l.loadDir(path)
+	} else {
+		err = l.loadArchive(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump %s: %w", path, err)
+	}
+	return &Dump{Memory: NewMemory(l.objects), logs: l.logs}, nil
+}
+
+// Logs returns the log of opts.Container. Dumps hold the logs as they were
+// collected, so opts.SinceTime is not applied.
+func (d *Dump) Logs(_ context.Context, namespace, pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	log, ok := d.logs[namespace+"/"+pod+"/"+opts.Container]
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("no logs for container %s of pod %s/%s in the dump", opts.Container, namespace, pod))
+	}
+	return io.NopCloser(bytes.NewReader(log)), nil
+}
+
+type dumpLoader struct {
+	objects []unstructured.Unstructured
+	logs    map[string][]byte
+}
+
+func (l *dumpLoader) loadDir(dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !isDumpFile(p) {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return l.add(filepath.ToSlash(rel), f)
+	})
+}
+
+func (l *dumpLoader) loadArchive(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = bufio.NewReader(f)
+	if magic, _ := r.(*bufio.Reader).Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("not a directory or tar archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !isDumpFile(hdr.Name) {
+			continue
+		}
+		if err := l.add(path.Clean(hdr.Name), tr); err != nil {
+			return err
+		}
+	}
+}
+
+// isDumpFile reports whether the file at name may hold objects or logs.
+func isDumpFile(name string) bool {
+	base := path.Base(filepath.ToSlash(name))
+	switch strings.ToLower(path.Ext(base)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return base == "logs.txt" || base == "current.log"
+}
+
+// add loads the file at name, a slash-separated path within the dump.
+func (l *dumpLoader) add(name string, r io.Reader) error {
+	segments := strings.Split(name, "/")
+	n := len(segments)
+	switch segments[n-1] {
+	case "logs.txt":
+		if n >= 3 {
+			return l.addLogSections(segments[n-3], segments[n-2], r)
+		}
+		return nil
+	case "current.log":
+		// namespaces/<namespace>/pods/<pod>/<container>/<container>/logs/current.log
+		if n >= 8 && segments[n-8] == "namespaces" && segments[n-6] == "pods" && segments[n-2] == "logs" {
+			log, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			l.logs[segments[n-7]+"/"+segments[n-5]+"/"+segments[n-3]] = log
+		}
+		return nil
+	}
+
+	kind, namespace := dumpPath(segments)
+	objects, err := manifest.ReadAs(r, kind)
+	if err != nil {
+		// Dumps hold other YAML and JSON too, e.g. must-gather's node and etcd data
+		return nil
+	}
+	for i := range objects {
+		if namespace != "" && objects[i].GetNamespace() == "" && objects[i].GetKind() != "Namespace" {
+			objects[i].SetNamespace(namespace)
+		}
+	}
+	l.objects = append(l.objects, objects...)
+	return nil
+}
+
+// addLogSections splits the logs.txt of a pod, written by cluster-info dump,
+// into the logs of its containers.
+func (l *dumpLoader) addLogSections(namespace, pod string, r io.Reader) error {
+	startPrefix := "==== START logs for container "
+	suffix := " of pod " + namespace + "/" + pod + " ===="
+	var container string
+	var log []byte
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, startPrefix) && strings.HasSuffix(line, suffix):
+			container = strings.TrimSuffix(strings.TrimPrefix(line, startPrefix), suffix)
+			log = []byte{}
+		case container != "" && line == "==== END logs for container "+container+suffix:
+			l.logs[namespace+"/"+pod+"/"+container] = log
+			container = ""
+		case container != "":
+			log = append(log, line...)
+			log = append(log, '\n')
+		}
+	}
+	return scanner.Err()
+}
+
+// dumpPath returns the kind of the objects in the dump file at segments, if
+// its path names a built-in resource, and, in must-gather dumps, their
+// namespace.
+func dumpPath(segments []string) (kind schema.GroupVersionKind, namespace string) {
+	n := len(segments)
+	resource := strings.TrimSuffix(segments[n-1], path.Ext(segments[n-1]))
+	for i := n - 1; i >= 0; i-- {
+		rest := segments[i+1:]
+		switch {
+		case segments[i] == "namespaces" && len(rest) >= 3:
+			namespace = rest[0]
+			if rest[1] == "pods" {
+				kind, _ = builtinKind("", "pods", true)
+				return kind, namespace
+			}
+			rest = rest[1:]
+		case segments[i] == "cluster-scoped-resources" && len(rest) >= 2:
+		default:
+			continue
+		}
+		// <group>/<resource>.yaml or <group>/<resource>/<name>.yaml
+		group := rest[0]
+		if group == "core" {
+			group = ""
+		}
+		if len(rest) > 2 {
+			resource = rest[1]
+		}
+		kind, _ = builtinKind(group, resource, true)
+		return kind, namespace
+	}
+
+	// cluster-info dump names files after resources, and its one irregular one
+	if resource == "replication-controllers" {
+		resource = "replicationcontrollers"
+	}
+	kind, _ = builtinKind("", resource, false)
+	return kind, ""
+}
+
+// builtinKind returns the kind of a built-in resource. Without the group,
+// the core group is preferred, then any but the deprecated extensions group.
+func builtinKind(group, resource string, groupKnown bool) (schema.GroupVersionKind, bool) {
+	var matches []schema.GroupVersionKind
+	for _, gvk := range builtinKinds() {
+		plural, _ := resourceOf(gvk)
+		if plural.Resource == resource && (!groupKnown || gvk.Group == group) {
+			matches = append(matches, gvk)
+		}
+	}
+	if len(matches) == 0 {
+		return schema.GroupVersionKind{}, false
+	}
+	rank := func(gvk schema.GroupVersionKind) string {
+		switch gvk.Group {
+		case "":
+			return "0"
+		case "extensions":
+			return "2"
+		}
+		return "1" + gvk.Group
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if ri, rj := rank(matches[i]), rank(matches[j]); ri != rj {
+			return ri < rj
+		}
+		// Any version lists the same objects; v1 keeps it readable
+		return matches[i].Version == "v1" && matches[j].Version != "v1"
+	})
+	return matches[0], true
+}
+
+// builtinKinds are the kinds of the built-in API types.
+var builtinKinds = sync.OnceValue(func() []schema.GroupVersionKind {
+	var kinds []schema.GroupVersionKind
+	for gvk := range scheme.Scheme.AllKnownTypes() {
+		if gvk.Version == runtime.APIVersionInternal || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		kinds = append(kinds, gvk)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+	return kinds
+})
@@ -0,0 +1,196 @@
+package source
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// clusterInfoDump is laid out as kubectl cluster-info dump writes it.
+var clusterInfoDump = map[string]string{
+	"nodes.json": `{"kind": "NodeList", "apiVersion": "v1", "items": [{"metadata": {"name": "node-1"}}]}`,
+	// Items of typed lists carry no kind of their own
+	"prod/pods.json": `{"kind": "PodList", "apiVersion": "v1", "items": [
+		{"metadata": {"name": "web-1", "namespace": "prod"}, "spec": {"containers": [{"name": "app"}, {"name": "proxy"}]}}
+	]}`,
+	// Neither does this list, so its file names the resource
+	"prod/deployments.json": `{"items": [{"metadata": {"name": "web", "namespace": "prod"}}]}`,
+	"prod/web-1/logs.txt": "==== START logs for container app of pod prod/web-1 ====\n" +
+		"listening\nready\n" +
+		"==== END logs for container app of pod prod/web-1 ====\n" +
+		"==== START logs for container proxy of pod prod/web-1 ====\n" +
+		"proxy up\n" +
+		"==== END logs for container proxy of pod prod/web-1 ====\n",
+}
+
+// mustGather is laid out as oc adm must-gather writes it, under the
+// directory of the image that gathered it.
+var mustGather = map[string]string{
+	"quay-io-must-gather/namespaces/prod/prod.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: prod\n",
+	"quay-io-must-gather/namespaces/prod/apps/deployments.yaml": `
+apiVersion: apps/v1
+kind: DeploymentList
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+`,
+	"quay-io-must-gather/namespaces/prod/pods/web-1/web-1.yaml":                      "metadata:\n  name: web-1\n",
+	"quay-io-must-gather/namespaces/prod/pods/web-1/app/app/logs/current.log":        "2024-05-01T10:00:00Z listening\n",
+	"quay-io-must-gather/cluster-scoped-resources/core/nodes/node-1.yaml":            "apiVersion: v1\nkind: Node\nmetadata:\n  name: node-1\n",
+	"quay-io-must-gather/etcd_info/member_list.json":                                 `[{"name": "etcd-0"}]`,
+	"quay-io-must-gather/cluster-scoped-resources/config.openshift.io/versions.yaml": "not: [valid",
+}
+
+func writeDump(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func writeArchive(t *testing.T, files map[string]string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "dump.tgz")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	var paths []string
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		content := files[path]
+		if err := tw.WriteHeader(&tar.Header{Name: "cluster-dump/" + path, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func listNames(t *testing.T, d *Dump, gvr schema.GroupVersionResource, namespace string) string {
+	t.Helper()
+	list, err := d.List(context.Background(), gvr, namespace, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	return names(list)
+}
+
+func readLogs(t *testing.T, d *Dump, namespace, pod, container string) string {
+	t.Helper()
+	logs, err := d.Logs(context.Background(), namespace, pod, &corev1.PodLogOptions{Container: container})
+	if err != nil {
+		t.Fatalf("Logs() error = %v", err)
+	}
+	b, _ := io.ReadAll(logs)
+	return string(b)
+}
+
+var (
+	podsGVR        = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	nodesGVR       = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+)
+
+func TestDumpClusterInfo(t *testing.T) {
+	for name, path := range map[string]string{
+		"directory": writeDump(t, clusterInfoDump),
+		"archive":   writeArchive(t, clusterInfoDump),
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := NewDump(path)
+			if err != nil {
+				t.Fatalf("NewDump() error = %v", err)
+			}
+			if got := listNames(t, d, podsGVR, "prod"); got != "web-1" {
+				t.Errorf("pods = %s, want web-1", got)
+			}
+			if got := listNames(t, d, nodesGVR, ""); got != "node-1" {
+				t.Errorf("nodes = %s, want node-1", got)
+			}
+			if got := listNames(t, d, deploymentsGVR, "prod"); got != "web" {
+				t.Errorf("deployments = %s, want web", got)
+			}
+
+			if got := readLogs(t, d, "prod", "web-1", "app"); got != "listening\nready\n" {
+				t.Errorf("Logs() = %q, want the app section", got)
+			}
+			if got := readLogs(t, d, "prod", "web-1", "proxy"); got != "proxy up\n" {
+				t.Errorf("Logs() = %q, want the proxy section", got)
+			}
+			if _, err := d.Logs(context.Background(), "prod", "web-1", &corev1.PodLogOptions{Container: "missing"}); !apierrors.IsBadRequest(err) {
+				t.Errorf("Logs() of a missing container error = %v, want BadRequest", err)
+			}
+		})
+	}
+}
+
+func TestDumpMustGather(t *testing.T) {
+	d, err := NewDump(writeDump(t, mustGather))
+	if err != nil {
+		t.Fatalf("NewDump() error = %v", err)
+	}
+	// Namespaces come from the path when objects leave them out
+	if got := listNames(t, d, deploymentsGVR, "prod"); got != "web" {
+		t.Errorf("deployments = %s, want web", got)
+	}
+	if got := listNames(t, d, podsGVR, "other"); got != "" {
+		t.Errorf("pods in other = %s, want none", got)
+	}
+	if got := listNames(t, d, podsGVR, "prod"); got != "web-1" {
+		t.Errorf("pods = %s, want web-1", got)
+	}
+	if got := listNames(t, d, nodesGVR, ""); got != "node-1" {
+		t.Errorf("nodes = %s, want node-1", got)
+	}
+	if got := listNames(t, d, schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, ""); got != "prod" {
+		t.Errorf("namespaces = %s, want prod", got)
+	}
+	if got := readLogs(t, d, "prod", "web-1", "app"); got != "2024-05-01T10:00:00Z listening\n" {
+		t.Errorf("Logs() = %q", got)
+	}
+}
+
+func TestDumpInvalid(t *testing.T) {
+	if _, err := NewDump(filepath.Join(t.TempDir(), "missing.tgz")); err == nil {
+		t.Error("NewDump() of a missing path succeeded")
+	}
+	notArchive := writeDump(t, map[string]string{"dump.tgz": "plain text"})
+	if _, err := NewDump(filepath.Join(notArchive, "dump.tgz")); err == nil {
+		t.Error("NewDump() of a file that is not an archive succeeded")
+	}
+}
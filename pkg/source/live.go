@@ -0,0 +1,77 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Live reads a cluster through its API server.
+type Live struct {
+	client    dynamic.Interface
+	discovery discovery.DiscoveryInterface // may be nil
+	clientset kubernetes.Interface         // reads logs; may be nil
+}
+
+// NewLive returns a Source backed by client. discovery and clientset may be
+// nil, leaving discovery and logs unsupported.
+func NewLive(client dynamic.Interface, discovery discovery.DiscoveryInterface, clientset kubernetes.Interface) *Live {
+	return &Live{client: client, discovery: discovery, clientset: clientset}
+}
+
+// NewLiveForConfig returns a Source reading the cluster config points at,
+// with discovery results cached for the life of the Source.
+func NewLiveForConfig(config *rest.Config) (*Live, error) {
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return NewLive(dynamicClient, memory.NewMemCacheClient(discoveryClient), clientset), nil
+}
+
+func (l *Live) List(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return l.client.Resource(gvr).Namespace(namespace).List(ctx, opts)
+}
+
+func (l *Live) Watch(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return l.client.Resource(gvr).Namespace(namespace).Watch(ctx, opts)
+}
+
+// ServerPreferredResources returns the resources the API server serves.
+// Partial results are returned along with the error when some API groups
+// fail to respond.
+func (l *Live) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	if l.discovery == nil {
+		return nil, fmt.Errorf("discovery is not available")
+	}
+	return discovery.ServerPreferredResources(l.discovery)
+}
+
+func (l *Live) Logs(ctx context.Context, namespace, pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	if l.clientset == nil {
+		return nil, fmt.Errorf("reading logs is not supported by this client")
+	}
+	return l.clientset.CoreV1().Pods(namespace).GetLogs(pod, opts).Stream(ctx)
+}
@@ -0,0 +1,130 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bangmodtechnology/kselect/pkg/manifest"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Memory serves a fixed set of objects, e.g. loaded from manifests or built
+// by tests. Objects are matched to resources by kind and the version is
+// ignored, since manifests may use any served version. Namespaced objects
+// without a namespace match every namespace, as they would be created in
+// whichever one they are applied to.
+type Memory struct {
+	objects []unstructured.Unstructured
+}
+
+// NewMemory returns a Source serving objects.
+func NewMemory(objects []unstructured.Unstructured) *Memory {
+	return &Memory{objects: objects}
+}
+
+// NewFiles returns a Source serving the objects in manifest files,
+// directories or manifest.Stdin, which reads stdin.
+func NewFiles(paths []string, stdin io.Reader) (*Memory, error) {
+	objects, err := manifest.Load(paths, stdin)
+	if err != nil {
+		return nil, err
+	}
+	return NewMemory(objects), nil
+}
+
+func (m *Memory) List(_ context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	labelSelector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	fieldSelector, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid field selector: %w", err)
+	}
+
+	list := &unstructured.UnstructuredList{}
+	for i := range m.objects {
+		obj := &m.objects[i]
+		if plural, _ := resourceOf(obj.GroupVersionKind()); plural.GroupResource() != gvr.GroupResource() {
+			continue
+		}
+		if ns := obj.GetNamespace(); namespace != "" && ns != "" && ns != namespace {
+			continue
+		}
+		if !labelSelector.Matches(labels.Set(obj.GetLabels())) || !fieldSelector.Matches(objectFields(obj, fieldSelector)) {
+			continue
+		}
+		list.Items = append(list.Items, *obj.DeepCopy())
+	}
+	return list, nil
+}
+
+// ServerPreferredResources describes the kinds among the objects the way
+// discovery describes served resources. A kind is namespaced if any of its
+// objects has a namespace.
+func (m *Memory) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	byGroupVersion := make(map[string]map[string]*metav1.APIResource)
+	for i := range m.objects {
+		obj := &m.objects[i]
+		gvk := obj.GroupVersionKind()
+		resources, ok := byGroupVersion[gvk.GroupVersion().String()]
+		if !ok {
+			resources = make(map[string]*metav1.APIResource)
+			byGroupVersion[gvk.GroupVersion().String()] = resources
+		}
+		plural, singular := resourceOf(gvk)
+		res, ok := resources[plural.Resource]
+		if !ok {
+			res = &metav1.APIResource{
+				Name:         plural.Resource,
+				SingularName: singular.Resource,
+				Kind:         gvk.Kind,
+				Verbs:        metav1.Verbs{"get", "list"},
+			}
+			resources[plural.Resource] = res
+		}
+		res.Namespaced = res.Namespaced || obj.GetNamespace() != ""
+	}
+
+	var lists []*metav1.APIResourceList
+	for gv, resources := range byGroupVersion {
+		list := &metav1.APIResourceList{GroupVersion: gv}
+		for _, res := range resources {
+			list.APIResources = append(list.APIResources, *res)
+		}
+		sort.Slice(list.APIResources, func(i, j int) bool { return list.APIResources[i].Name < list.APIResources[j].Name })
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].GroupVersion < lists[j].GroupVersion })
+	return lists, nil
+}
+
+// resourceOf guesses the plural and singular resource of a kind, as kubectl
+// does for kinds it cannot discover (Ingress → ingresses).
+func resourceOf(gvk schema.GroupVersionKind) (plural, singular schema.GroupVersionResource) {
+	plural, singular = meta.UnsafeGuessKindToResource(gvk)
+	// The one built-in kind that is already plural
+	if gvk.Kind == "Endpoints" {
+		plural.Resource, singular.Resource = "endpoints", "endpoints"
+	}
+	return plural, singular
+}
+
+// objectFields returns the values of the fields selector filters on.
+func objectFields(obj *unstructured.Unstructured, selector fields.Selector) fields.Set {
+	set := fields.Set{}
+	for _, req := range selector.Requirements() {
+		value, _, _ := unstructured.NestedString(obj.Object, strings.Split(req.Field, ".")...)
+		set[req.Field] = value
+	}
+	return set
+}
@@ -0,0 +1,108 @@
+package source
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func object(apiVersion, kind, namespace, name string, labels map[string]interface{}) unstructured.Unstructured {
+	metadata := map[string]interface{}{"name": name}
+	if namespace != "" {
+		metadata["namespace"] = namespace
+	}
+	if labels != nil {
+		metadata["labels"] = labels
+	}
+	return unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": apiVersion, "kind": kind, "metadata": metadata}}
+}
+
+func names(list *unstructured.UnstructuredList) string {
+	var got []string
+	for _, item := range list.Items {
+		got = append(got, item.GetName())
+	}
+	return strings.Join(got, ",")
+}
+
+func TestMemoryList(t *testing.T) {
+	m := NewMemory([]unstructured.Unstructured{
+		object("apps/v1", "Deployment", "prod", "web", map[string]interface{}{"app": "web"}),
+		object("apps/v1beta1", "Deployment", "dev", "old", nil),
+		object("apps/v1", "Deployment", "", "anywhere", map[string]interface{}{"app": "batch"}),
+		object("v1", "Endpoints", "prod", "web", nil),
+		object("v1", "Pod", "prod", "web-1", nil),
+	})
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	tests := []struct {
+		name      string
+		gvr       schema.GroupVersionResource
+		namespace string
+		opts      metav1.ListOptions
+		want      string
+	}{
+		{"all namespaces and versions", deployments, "", metav1.ListOptions{}, "web,old,anywhere"},
+		{"namespace", deployments, "prod", metav1.ListOptions{}, "web,anywhere"},
+		{"label selector", deployments, "", metav1.ListOptions{LabelSelector: "app in (web, api)"}, "web"},
+		{"field selector", deployments, "", metav1.ListOptions{FieldSelector: "metadata.namespace!=prod"}, "old,anywhere"},
+		{"plural kind", schema.GroupVersionResource{Version: "v1", Resource: "endpoints"}, "", metav1.ListOptions{}, "web"},
+		{"other group", schema.GroupVersionResource{Group: "extensions", Version: "v1", Resource: "deployments"}, "", metav1.ListOptions{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := m.List(context.Background(), tt.gvr, tt.namespace, tt.opts)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if got := names(list); got != tt.want {
+				t.Errorf("List() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := m.List(context.Background(), deployments, "", metav1.ListOptions{LabelSelector: "app in ("}); err == nil {
+		t.Error("List() with an invalid selector succeeded")
+	}
+}
+
+func TestMemoryListCopies(t *testing.T) {
+	m := NewMemory([]unstructured.Unstructured{object("v1", "Pod", "prod", "web-1", nil)})
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	list, _ := m.List(context.Background(), pods, "", metav1.ListOptions{})
+	list.Items[0].SetName("changed")
+	list, _ = m.List(context.Background(), pods, "", metav1.ListOptions{})
+	if got := names(list); got != "web-1" {
+		t.Errorf("List() after changing a listed object = %s, want web-1", got)
+	}
+}
+
+func TestMemoryServerPreferredResources(t *testing.T) {
+	m := NewMemory([]unstructured.Unstructured{
+		object("example.com/v1", "Widget", "", "a", nil),
+		object("example.com/v1", "Widget", "prod", "b", nil),
+		object("example.com/v1", "Gadget", "", "c", nil),
+		object("v1", "Pod", "prod", "web-1", nil),
+	})
+	lists, err := m.ServerPreferredResources()
+	if err != nil {
+		t.Fatalf("ServerPreferredResources() error = %v", err)
+	}
+	if len(lists) != 2 || lists[0].GroupVersion != "example.com/v1" || lists[1].GroupVersion != "v1" {
+		t.Fatalf("Unexpected group versions: %v", lists)
+	}
+	resources := lists[0].APIResources
+	if len(resources) != 2 {
+		t.Fatalf("Expected gadgets and widgets, got %v", resources)
+	}
+	if res := resources[0]; res.Name != "gadgets" || res.SingularName != "gadget" || res.Kind != "Gadget" || res.Namespaced {
+		t.Errorf("Unexpected gadgets resource: %+v", res)
+	}
+	if res := resources[1]; res.Name != "widgets" || !res.Namespaced {
+		t.Errorf("Widgets have a namespaced object, got %+v", res)
+	}
+}
@@ -0,0 +1,36 @@
+// Package source provides the objects queries run against: a live cluster,
+// manifest files, a cluster dump or objects held in memory.
+package source
+
+import (
+	"context"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Source lists objects as the API server would. namespace "" lists all
+// namespaces; opts carries the label and field selectors.
+type Source interface {
+	List(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+}
+
+// Watcher is implemented by sources that can stream changes to objects.
+type Watcher interface {
+	Watch(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// Discoverer is implemented by sources that can tell which resources they
+// serve, so resources missing from the registry can still be queried.
+type Discoverer interface {
+	ServerPreferredResources() ([]*metav1.APIResourceList, error)
+}
+
+// LogStreamer is implemented by sources that hold container logs.
+type LogStreamer interface {
+	Logs(ctx context.Context, namespace, pod string, opts *corev1.PodLogOptions) (io.ReadCloser, error)
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -54,6 +55,9 @@ type model struct {
 	table           table.Model
 	query           *parser.Query
 	exec            *executor.Executor
+	ctx             context.Context    // cancelled when the TUI quits
+	cancelRefresh   context.CancelFunc // cancels the refresh in flight, if any
+	refreshGen      int                // generation of the latest refresh; older results are dropped
 	allResults      []map[string]interface{}
 	filteredResults []map[string]interface{}
 	fields          []string
@@ -62,21 +66,29 @@ type model struct {
 	sortCol         int
 	sortDesc        bool
 	elapsed         time.Duration
+	cacheStatus     string // "cached" or "partly cached" if results came from the cache
 	err             error
 	width           int
 	height          int
 }
 
 type refreshMsg struct {
-	results []map[string]interface{}
-	fields  []string
-	elapsed time.Duration
-	err     error
+	gen         int
+	results     []map[string]interface{}
+	fields      []string
+	elapsed     time.Duration
+	cacheStatus string
+	err         error
 }
 
-// Run starts the TUI with pre-fetched results.
-func Run(exec *executor.Executor, query *parser.Query, results []map[string]interface{}, fields []string, elapsed time.Duration) error {
+// Run starts the TUI with pre-fetched results. Refreshes run under ctx and
+// are cancelled when the TUI quits.
+func Run(ctx context.Context, exec *executor.Executor, query *parser.Query, results []map[string]interface{}, fields []string, elapsed time.Duration, cacheStatus string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	m := newModel(exec, query, results, fields, elapsed)
+	m.ctx = ctx
+	m.cacheStatus = cacheStatus
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
@@ -86,6 +98,7 @@ func newModel(exec *executor.Executor, query *parser.Query, results []map[string
 	m := model{
 		query:      query,
 		exec:       exec,
+		ctx:        context.Background(),
 		allResults: results,
 		fields:     fields,
 		elapsed:    elapsed,
@@ -117,6 +130,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case refreshMsg:
+		if msg.gen != m.refreshGen {
+			return m, nil
+		}
+		m.cancelRefresh()
+		m.cancelRefresh = nil
 		if msg.err != nil {
 			m.err = msg.err
 			return m, nil
@@ -124,6 +142,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.allResults = msg.results
 		m.fields = msg.fields
 		m.elapsed = msg.elapsed
+		m.cacheStatus = msg.cacheStatus
 		m.err = nil
 		m.applyFilter()
 		m.applySort()
@@ -139,6 +158,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "q", "ctrl+c":
+		if m.cancelRefresh != nil {
+			m.cancelRefresh()
+		}
 		return m, tea.Quit
 	case "/":
 		m.filtering = true
@@ -153,7 +175,14 @@ func (m model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.table = m.buildTable()
 		return m, nil
 	case "r":
-		return m, m.refreshCmd()
+		// A new refresh supersedes the one in flight
+		if m.cancelRefresh != nil {
+			m.cancelRefresh()
+		}
+		ctx, cancel := context.WithCancel(m.ctx)
+		m.cancelRefresh = cancel
+		m.refreshGen++
+		return m, m.refreshCmd(ctx, m.refreshGen)
 	}
 
 	var cmd tea.Cmd
@@ -251,12 +280,16 @@ func (m *model) applySort() {
 	})
 }
 
-func (m model) refreshCmd() tea.Cmd {
+func (m model) refreshCmd(ctx context.Context, gen int) tea.Cmd {
 	return func() tea.Msg {
+		// Refreshing means reading the cluster again
+		m.exec.Refresh()
 		start := time.Now()
-		results, fields, err := m.exec.Execute(m.query)
+		cacheBefore := m.exec.CacheStats()
+		results, fields, err := m.exec.ExecuteContext(ctx, m.query)
 		elapsed := time.Since(start)
-		return refreshMsg{results: results, fields: fields, elapsed: elapsed, err: err}
+		cacheStatus := m.exec.CacheStats().Sub(cacheBefore).String()
+		return refreshMsg{gen: gen, results: results, fields: fields, elapsed: elapsed, cacheStatus: cacheStatus, err: err}
 	}
 }
 
@@ -351,8 +384,12 @@ func (m model) View() string {
 		}
 		sortInfo = fmt.Sprintf(" | Sort: %s %s", m.fields[m.sortCol], dir)
 	}
-	header := fmt.Sprintf("kselect | %s (%s) | %d items%s | (%.2fs)",
-		m.query.Resource, ns, len(m.filteredResults), sortInfo, m.elapsed.Seconds())
+	timing := fmt.Sprintf("%.2fs", m.elapsed.Seconds())
+	if m.cacheStatus != "" {
+		timing += ", " + m.cacheStatus
+	}
+	header := fmt.Sprintf("kselect | %s (%s) | %d items%s | (%s)",
+		m.query.Resource, ns, len(m.filteredResults), sortInfo, timing)
 	b.WriteString(headerStyle.Render(header))
 	b.WriteString("\n")
 
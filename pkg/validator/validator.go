@@ -46,11 +46,28 @@ func New(reg *registry.Registry) *Validator {
 
 // Validate validates a parsed query
 func (v *Validator) Validate(query *parser.Query) error {
+	// WITH relations are validated first and then visible as resources
+	if len(query.With) > 0 {
+		return v.validateWith(query)
+	}
+
+	// Validate each UNION / INTERSECT / EXCEPT operand on its own
+	if len(query.SetOps) > 0 {
+		return v.validateSetOperations(query)
+	}
+
 	// Validate resource exists
 	if err := v.validateResource(query.Resource); err != nil {
 		return err
 	}
 
+	// Validate the pod query of FROM logs(query)
+	if query.LogsOf != nil {
+		if err := v.Validate(query.LogsOf); err != nil {
+			return fmt.Errorf("logs(): %w", err)
+		}
+	}
+
 	// Get resource definition
 	resource, ok := v.registry.Get(query.Resource)
 	if !ok {
@@ -112,6 +129,102 @@ func (v *Validator) Validate(query *parser.Query) error {
 	return nil
 }
 
+// validateWith validates each WITH query in order, registering its output
+// columns as a resource so later queries can select from it.
+func (v *Validator) validateWith(query *parser.Query) error {
+	scoped := New(v.registry.Clone())
+	for _, cte := range query.With {
+		if err := scoped.Validate(cte.Query); err != nil {
+			return fmt.Errorf("WITH %s: %w", cte.Name, err)
+		}
+		scoped.registry.Register(registry.NewRelationDefinition(cte.Name, scoped.outputColumns(cte.Query)))
+	}
+
+	outer := *query
+	outer.With = nil
+	return scoped.Validate(&outer)
+}
+
+// validateSetOperations validates the head query and every set operation
+// operand, and checks that all of them return the same number of columns.
+func (v *Validator) validateSetOperations(query *parser.Query) error {
+	head := *query
+	head.SetOps = nil
+	if err := v.Validate(&head); err != nil {
+		return err
+	}
+
+	want := len(v.outputColumns(&head))
+	for _, op := range query.SetOps {
+		if err := v.Validate(op.Query); err != nil {
+			return err
+		}
+		if got := len(v.outputColumns(op.Query)); got != want {
+			return &ValidationError{
+				Message: fmt.Sprintf("%s operands must return the same number of columns (%d vs %d)", op.Operator, want, got),
+			}
+		}
+	}
+	return nil
+}
+
+// outputColumns returns the column names a valid query produces, following
+// the executor's rules for *, default fields and aggregate aliases.
+func (v *Validator) outputColumns(query *parser.Query) []string {
+	if len(query.Aggregates) > 0 || len(query.GroupBy) > 0 {
+		seen := make(map[string]bool)
+		var columns []string
+		add := func(col string) {
+			if col != "" && !seen[col] {
+				seen[col] = true
+				columns = append(columns, col)
+			}
+		}
+		for _, f := range query.GroupBy {
+			add(f)
+		}
+		for _, f := range query.Fields {
+			add(f)
+		}
+		for _, agg := range query.Aggregates {
+			add(agg.Alias)
+		}
+		return columns
+	}
+
+	if len(query.Fields) > 0 && !(len(query.Fields) == 1 && query.Fields[0] == "*") {
+		return query.Fields
+	}
+
+	var columns []string
+	if def, ok := v.registry.Get(query.Resource); ok {
+		columns = append(columns, defaultColumns(def, query.Prefix(), len(query.Joins) > 0)...)
+	}
+	for i := range query.Joins {
+		if def, ok := v.registry.Get(query.Joins[i].Resource); ok {
+			columns = append(columns, defaultColumns(def, query.Joins[i].Prefix(), true)...)
+		}
+	}
+	return columns
+}
+
+// defaultColumns returns the fields * expands to for def, prefixed in JOINs.
+func defaultColumns(def *registry.ResourceDefinition, prefix string, prefixed bool) []string {
+	columns := append([]string(nil), def.DefaultFields...)
+	if len(columns) == 0 {
+		for name := range def.Fields {
+			columns = append(columns, name)
+		}
+		sort.Strings(columns)
+	}
+	if prefixed {
+		for i, col := range columns {
+			columns[i] = prefix + "." + col
+		}
+	}
+	return columns
+}
+
 // validateResource checks if a resource exists
 func (v *Validator) validateResource(resourceName string) error {
 	if resourceName == "" {
@@ -147,15 +260,14 @@ func (v *Validator) validateFields(resource *registry.ResourceDefinition, fields
 		// Resolve alias to canonical name
 		canonicalField := resource.ResolveFieldAlias(field)
 
-		// Check if field exists (including dot-notation map sub-fields like labels.app)
-		if _, ok := resource.Fields[canonicalField]; !ok {
-			if _, _, ok := resource.IsMapSubField(canonicalField); !ok {
-				// Find similar field names
-				suggestions := v.findSimilarFields(resource, field)
-				return &ValidationError{
-					Message:     fmt.Sprintf("Field '%s' not found in resource '%s'", field, resource.Name),
-					Suggestions: suggestions,
-				}
+		// Check if field exists (including dot-notation map sub-fields like labels.app
+		// and virtual owner fields)
+		if !resource.HasField(canonicalField) {
+			// Find similar field names
+			suggestions := v.findSimilarFields(resource, field)
+			return &ValidationError{
+				Message:     fmt.Sprintf("Field '%s' not found in resource '%s'", field, resource.Name),
+				Suggestions: suggestions,
 			}
 		}
 	}
@@ -179,13 +291,11 @@ func (v *Validator) validateConditionGroup(resource *registry.ResourceDefinition
 		canonicalField := resource.ResolveFieldAlias(cond.Field)
 
 		// Check if field exists (including dot-notation map sub-fields)
-		if _, ok := resource.Fields[canonicalField]; !ok {
-			if _, _, ok := resource.IsMapSubField(canonicalField); !ok {
-				suggestions := v.findSimilarFields(resource, cond.Field)
-				return &ValidationError{
-					Message:     fmt.Sprintf("Field '%s' in WHERE clause not found in resource '%s'", cond.Field, resource.Name),
-					Suggestions: suggestions,
-				}
+		if !resource.HasField(canonicalField) {
+			suggestions := v.findSimilarFields(resource, cond.Field)
+			return &ValidationError{
+				Message:     fmt.Sprintf("Field '%s' in WHERE clause not found in resource '%s'", cond.Field, resource.Name),
+				Suggestions: suggestions,
 			}
 		}
 	}
@@ -249,13 +359,11 @@ func (v *Validator) validateOrderBy(resource *registry.ResourceDefinition, order
 			canonicalField := resource.ResolveFieldAlias(ob.Field)
 
 			// Check if field exists in resource (including dot-notation map sub-fields)
-			if _, ok := resource.Fields[canonicalField]; !ok {
-				if _, _, ok := resource.IsMapSubField(canonicalField); !ok {
-					suggestions := v.findSimilarFields(resource, ob.Field)
-					return &ValidationError{
-						Message:     fmt.Sprintf("Field '%s' in ORDER BY clause not found in resource '%s'", ob.Field, resource.Name),
-						Suggestions: suggestions,
-					}
+			if !resource.HasField(canonicalField) {
+				suggestions := v.findSimilarFields(resource, ob.Field)
+				return &ValidationError{
+					Message:     fmt.Sprintf("Field '%s' in ORDER BY clause not found in resource '%s'", ob.Field, resource.Name),
+					Suggestions: suggestions,
 				}
 			}
 		}
@@ -271,13 +379,11 @@ func (v *Validator) validateGroupBy(resource *registry.ResourceDefinition, group
 		canonicalField := resource.ResolveFieldAlias(field)
 
 		// Check if field exists (including dot-notation map sub-fields)
-		if _, ok := resource.Fields[canonicalField]; !ok {
-			if _, _, ok := resource.IsMapSubField(canonicalField); !ok {
-				suggestions := v.findSimilarFields(resource, field)
-				return &ValidationError{
-					Message:     fmt.Sprintf("Field '%s' in GROUP BY clause not found in resource '%s'", field, resource.Name),
-					Suggestions: suggestions,
-				}
+		if !resource.HasField(canonicalField) {
+			suggestions := v.findSimilarFields(resource, field)
+			return &ValidationError{
+				Message:     fmt.Sprintf("Field '%s' in GROUP BY clause not found in resource '%s'", field, resource.Name),
+				Suggestions: suggestions,
 			}
 		}
 	}
@@ -296,7 +402,7 @@ func (v *Validator) validateAggregates(resource *registry.ResourceDefinition, ag
 		// Validate field exists
 		if agg.Field != "" && agg.Field != "*" {
 			canonicalField := resource.ResolveFieldAlias(agg.Field)
-			if _, ok := resource.Fields[canonicalField]; !ok {
+			if !resource.HasField(canonicalField) {
 				suggestions := v.findSimilarFields(resource, agg.Field)
 				return &ValidationError{
 					Message:     fmt.Sprintf("Field '%s' in %s() aggregation not found in resource '%s'", agg.Field, agg.Function, resource.Name),
@@ -443,13 +549,11 @@ func (v *Validator) validateHavingGroup(resource *registry.ResourceDefinition, g
 			}
 
 			// Also validate that field exists (including dot-notation map sub-fields)
-			if _, ok := resource.Fields[canonicalField]; !ok {
-				if _, _, ok := resource.IsMapSubField(canonicalField); !ok {
-					suggestions := v.findSimilarFields(resource, cond.Field)
-					return &ValidationError{
-						Message:     fmt.Sprintf("Field '%s' in HAVING clause not found in resource '%s'", cond.Field, resource.Name),
-						Suggestions: suggestions,
-					}
+			if !resource.HasField(canonicalField) {
+				suggestions := v.findSimilarFields(resource, cond.Field)
+				return &ValidationError{
+					Message:     fmt.Sprintf("Field '%s' in HAVING clause not found in resource '%s'", cond.Field, resource.Name),
+					Suggestions: suggestions,
 				}
 			}
 		}
@@ -489,8 +593,7 @@ func (v *Validator) validateAggregationConsistency(query *parser.Query) error {
 
 	// If there is GROUP BY, all non-aggregate fields must be in GROUP BY
 	if hasGroupBy {
-		reg := registry.GetGlobalRegistry()
-		resource, ok := reg.Get(query.Resource)
+		resource, ok := v.registry.Get(query.Resource)
 		if !ok {
 			return &ValidationError{Message: fmt.Sprintf("Resource '%s' not found", query.Resource)}
 		}
@@ -200,6 +200,14 @@ func TestValidateQuery(t *testing.T) {
 		{"invalid ORDER BY", "name FROM pod ORDER BY invalid", true},
 		{"valid aggregation", "namespace, COUNT FROM pod GROUP BY namespace", false},
 		{"valid SUM", "namespace, SUM.restarts FROM pod GROUP BY namespace", false},
+		{"valid raw path", "name, .spec.nodeName FROM pod WHERE annotations['a/b'] = x ORDER BY status.startTime", false},
+		{"invalid raw path", "name FROM pod WHERE spec..nodeName = x", true},
+		{"valid WITH", "WITH busy AS (SELECT name, restarts FROM pod WHERE restarts > 5) SELECT name FROM busy ORDER BY restarts", false},
+		{"invalid WITH column", "WITH busy AS (SELECT name FROM pod) SELECT status FROM busy", true},
+		{"invalid WITH query", "WITH busy AS (SELECT invalid FROM pod) SELECT name FROM busy", true},
+		{"valid UNION", "name, namespace FROM pod UNION name, replicas FROM deployment", false},
+		{"UNION column count mismatch", "name FROM pod UNION name, replicas FROM deployment", true},
+		{"invalid EXCEPT operand", "name FROM pod EXCEPT invalid FROM deployment", true},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,181 @@
+package kselect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the type of a column's values.
+type Type string
+
+const (
+	TypeString Type = "string"
+	TypeInt    Type = "int"   // int64
+	TypeFloat  Type = "float" // float64
+	TypeBool   Type = "bool"  // bool
+	TypeTime   Type = "time"  // time.Time
+	TypeList   Type = "list"  // []interface{}
+	TypeMap    Type = "map"   // map[string]interface{}
+)
+
+// Column describes one column of a Result.
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Result is the outcome of a query: its columns, in the order the query
+// selects them, and its rows.
+type Result struct {
+	Columns []Column
+	Rows    []Row
+}
+
+// Row is one row of a Result. Values hold the column values in column order,
+// converted to the Go type of their column; a value that does not fit its
+// column type, such as one number per container of a pod, is kept as read.
+// Missing values are nil.
+type Row struct {
+	Values  []interface{}
+	columns map[string]int
+}
+
+func newResult(columns []Column, rows []map[string]interface{}) *Result {
+	index := make(map[string]int, len(columns))
+	for i, col := range columns {
+		index[col.Name] = i
+	}
+	result := &Result{Columns: columns, Rows: make([]Row, len(rows))}
+	for i, row := range rows {
+		values := make([]interface{}, len(columns))
+		for j, col := range columns {
+			values[j] = convert(row[col.Name], col.Type)
+		}
+		result.Rows[i] = Row{Values: values, columns: index}
+	}
+	return result
+}
+
+// Get returns the value of column name, or nil if the row has no such column.
+func (r Row) Get(name string) interface{} {
+	i, ok := r.columns[name]
+	if !ok {
+		return nil
+	}
+	return r.Values[i]
+}
+
+// String returns the value of column name as text; nil is "".
+func (r Row) String(name string) string {
+	switch v := r.Get(name).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Int returns the value of column name as an integer, and whether it is one.
+func (r Row) Int(name string) (int64, bool) {
+	return toInt(r.Get(name))
+}
+
+// Float returns the value of column name as a number, and whether it is one.
+func (r Row) Float(name string) (float64, bool) {
+	return toFloat(r.Get(name))
+}
+
+// Time returns the value of column name as a time, and whether it is one.
+func (r Row) Time(name string) (time.Time, bool) {
+	t, ok := r.Get(name).(time.Time)
+	return t, ok
+}
+
+// convert returns value as the Go type of typ, or value itself if it does
+// not convert.
+func convert(value interface{}, typ Type) interface{} {
+	switch typ {
+	case TypeInt:
+		if n, ok := toInt(value); ok {
+			return n
+		}
+	case TypeFloat:
+		if f, ok := toFloat(value); ok {
+			return f
+		}
+	case TypeTime:
+		if s, ok := value.(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return t
+			}
+		}
+	}
+	return value
+}
+
+func toInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), true
+		}
+	case string:
+		if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// inferType returns the type of the first value of field in rows, for
+// columns no resource declares, e.g. those of WITH relations.
+func inferType(rows []map[string]interface{}, field string) Type {
+	for _, row := range rows {
+		switch v := row[field].(type) {
+		case nil:
+			continue
+		case bool:
+			return TypeBool
+		case int, int64:
+			return TypeInt
+		case float64:
+			if v == float64(int64(v)) {
+				return TypeInt
+			}
+			return TypeFloat
+		case []interface{}:
+			return TypeList
+		case map[string]interface{}:
+			return TypeMap
+		default:
+			return TypeString
+		}
+	}
+	return TypeString
+}